@@ -2,6 +2,11 @@
 // @version		1.0
 // @description	Сервис для создания коротких ссылок и получения аналитики по переходам.
 // @BasePath		/
+//
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				API-ключ в формате "Bearer <key>".
 package main
 
 import (
@@ -15,21 +20,32 @@ import (
 	"shortener/src/internal/application/config"
 	"shortener/src/internal/application/contracts"
 	"shortener/src/internal/application/services"
+	apikey "shortener/src/internal/domain/api_key"
+	brandeddomain "shortener/src/internal/domain/branded_domain"
 	shortlink "shortener/src/internal/domain/short_link"
 	"shortener/src/internal/domain/visit"
 	"shortener/src/internal/infrastructure/cache"
+	codepolicy "shortener/src/internal/infrastructure/code_policy"
 	"shortener/src/internal/infrastructure/data"
 	"shortener/src/internal/infrastructure/data/repositories"
+	domainlists "shortener/src/internal/infrastructure/domain_lists"
+	"shortener/src/internal/infrastructure/geoip"
 	"shortener/src/internal/infrastructure/kafka"
+	"shortener/src/internal/infrastructure/metadata"
+	qrcode "shortener/src/internal/infrastructure/qr_code"
 	generator "shortener/src/internal/infrastructure/short_link_generator"
+	"shortener/src/internal/web_api/auth"
 	"shortener/src/internal/web_api/controllers"
 	"shortener/src/internal/web_api/public"
+	"shortener/src/internal/web_api/validation"
 	"shortener/src/pkg/logger"
 	"sync"
 	"syscall"
 	"time"
 
 	_ "shortener/src/internal/web_api/docs"
+	// Часовые пояса правил (timezone) нужны и в образе alpine без tzdata.
+	_ "time/tzdata"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -54,7 +70,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	visitRepository, shortLinkRepository := initRepositories(db, retry.Strategy{
+	visitRepository, shortLinkRepository, apiKeyRepository, domainRepository := initRepositories(db, retry.Strategy{
 		Attempts: 3,
 		Delay:    time.Duration(0.5 * float64(time.Second)),
 		Backoff:  2,
@@ -67,7 +83,12 @@ func main() {
 		Backoff:  2,
 	})
 
-	codeGenerator := generator.NewRandomShortCodeGenerator()
+	codePolicy := codepolicy.NewWordListPolicy(cfg.ShortCode.ReservedWords)
+	codeGenerator := initGenerator(cfg.ShortCode, codePolicy, db, retry.Strategy{
+		Attempts: 3,
+		Delay:    time.Duration(0.5 * float64(time.Second)),
+		Backoff:  2,
+	})
 
 	redisClient := redis.New(cfg.Redis.Host, cfg.Redis.Password, cfg.Redis.DB)
 	redisCache := cache.NewRedis(redisClient, retry.Strategy{
@@ -76,19 +97,86 @@ func main() {
 		Backoff:  2,
 	})
 
+	apiKeyService := services.NewAPIKeyService(apiKeyRepository)
+	domainService := services.NewDomainService(domainRepository, redisCache, cfg.URLPolicy.PublicHosts)
+
+	urlLists, err := domainlists.NewFileLists(cfg.URLPolicy.ListsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	urlPolicy := services.NewURLPolicy(cfg.URLPolicy, domainService, urlLists)
+
 	visitService, shortLinkService := initServices(
 		visitRepository,
 		shortLinkRepository,
 		producer,
 		codeGenerator,
+		codePolicy,
+		cfg.ShortCode,
+		urlPolicy,
 		redisCache,
+		cfg.URLPolicy.StripTrackingParams,
 	)
 
-	validate := validator.New()
+	unlockService, err := services.NewUnlockService(redisCache, cfg.Unlock)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	handoffService, err := services.NewHandoffService(redisCache, cfg.Handoff)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	validate, err := validation.New(cfg.ShortCode)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	shortLinkController, analyticsController := initControllers(shortLinkService, visitService, validate)
+	qrCodeService := services.NewQRCodeService(qrcode.NewEncoder(), redisCache, cfg.QRCode.CacheTTL)
 
-	server := initServer(cfg.HTTP, shortLinkController, analyticsController)
+	metadataProducer := initKafkaProducer(
+		wbfkafka.NewProducer([]string{cfg.Kafka.Broker}, cfg.Kafka.MetadataTopic),
+		retry.Strategy{
+			Attempts: 3,
+			Delay:    time.Duration(0.5 * float64(time.Second)),
+			Backoff:  2,
+		},
+	)
+	metadataService := services.NewMetadataService(
+		shortLinkRepository,
+		metadata.NewHTTPFetcher(cfg.Metadata),
+		urlPolicy,
+		metadataProducer,
+		redisCache,
+	)
+
+	shortLinkController, analyticsController, qrCodeController, apiKeyController, domainController := initControllers(
+		cfg.Auth,
+		cfg.QRCode,
+		shortLinkService,
+		domainService,
+		unlockService,
+		handoffService,
+		urlPolicy,
+		visitService,
+		metadataService,
+		geoip.NewHeaderResolver(cfg.GeoIP.CountryHeaders),
+		qrCodeService,
+		apiKeyService,
+		validate,
+	)
+
+	server := initServer(
+		cfg.HTTP,
+		cfg.Auth.AdminToken,
+		apiKeyService,
+		shortLinkController,
+		analyticsController,
+		qrCodeController,
+		apiKeyController,
+		domainController,
+	)
 	go func() {
 		err := server.ListenAndServe()
 		if err != nil {
@@ -113,11 +201,53 @@ func main() {
 	consumer.Start(ctx)
 	logger.Info("visit consumer started")
 
-	gracefulShutdown(cancel, server, consumer, redisClient, db)
+	metadataConsumer := kafka.NewMetadataConsumer(
+		wbfkafka.NewConsumer([]string{cfg.Kafka.Broker}, cfg.Kafka.MetadataTopic, cfg.Kafka.MetadataGroupID),
+		metadataService,
+		retry.Strategy{
+			Attempts: 3,
+			Delay:    time.Duration(0.5 * float64(time.Second)),
+			Backoff:  2,
+		},
+		cfg.Metadata.Workers,
+	)
+	metadataConsumer.Start(ctx)
+	logger.Info("metadata consumer started")
+
+	go urlLists.Watch(ctx, cfg.URLPolicy.ListsReloadInterval)
+
+	gracefulShutdown(cancel, server, consumer, metadataConsumer, redisClient, db)
 }
 
-func initRepositories(db *dbpg.DB, retry retry.Strategy) (visit.VisitRepository, shortlink.ShortLinkRepository) {
-	return repositories.NewVisitRepository(db, retry), repositories.NewShortLinkRepository(db, retry)
+func initRepositories(
+	db *dbpg.DB,
+	retry retry.Strategy,
+) (
+	visit.VisitRepository,
+	shortlink.ShortLinkRepository,
+	apikey.APIKeyRepository,
+	brandeddomain.DomainRepository,
+) {
+	return repositories.NewVisitRepository(db, retry),
+		repositories.NewShortLinkRepository(db, retry),
+		repositories.NewAPIKeyRepository(db, retry),
+		repositories.NewDomainRepository(db, retry)
+}
+
+func initGenerator(
+	cfg config.ShortCodeConfig,
+	policy shortlink.CodePolicy,
+	db *dbpg.DB,
+	retry retry.Strategy,
+) shortlink.ShortLinkGenerator {
+	switch cfg.Generator {
+	case config.ShortCodeGeneratorSequential:
+		return generator.NewSequentialShortCodeGenerator(repositories.NewShortCodeCounterRepository(db, retry), cfg)
+	case config.ShortCodeGeneratorHash:
+		return generator.NewHashShortCodeGenerator(cfg)
+	default:
+		return generator.NewRandomShortCodeGenerator(cfg, policy)
+	}
 }
 
 func initVisitConsumer(
@@ -137,25 +267,77 @@ func initServices(
 	shortLinkRepository shortlink.ShortLinkRepository,
 	producer contracts.MessageProducer,
 	generator shortlink.ShortLinkGenerator,
+	codePolicy shortlink.CodePolicy,
+	shortCode config.ShortCodeConfig,
+	urlPolicy shortlink.URLPolicy,
 	redis contracts.Cache,
+	stripTrackingParams bool,
 ) (visit.VisitService, shortlink.ShortLinkService) {
 	return services.NewVisitService(visitRepository, producer),
-		services.NewShortLinkService(shortLinkRepository, generator, redis)
+		services.NewShortLinkService(
+			shortLinkRepository,
+			generator,
+			codePolicy,
+			shortCode.AliasMinLength,
+			shortCode.AliasMaxLength,
+			urlPolicy,
+			redis,
+			stripTrackingParams,
+		)
 }
 
 func initControllers(
+	authCfg config.AuthConfig,
+	qrCodeCfg config.QRCodeConfig,
 	shortLinkService shortlink.ShortLinkService,
+	domainService brandeddomain.DomainService,
+	unlockService shortlink.UnlockService,
+	handoffService shortlink.HandoffService,
+	urlPolicy shortlink.URLPolicy,
 	visitService visit.VisitService,
+	metadataService shortlink.MetadataService,
+	countryResolver visit.CountryResolver,
+	qrCodeService shortlink.QRCodeService,
+	apiKeyService apikey.APIKeyService,
 	validator *validator.Validate,
-) (*controllers.ShortLinkController, *controllers.AnalyticsController) {
-	return controllers.NewShortLinkController(shortLinkService, visitService, validator),
-		controllers.NewAnalyticsController(visitService)
+) (
+	*controllers.ShortLinkController,
+	*controllers.AnalyticsController,
+	*controllers.QRCodeController,
+	*controllers.APIKeyController,
+	*controllers.DomainController,
+) {
+	shortLinkController := controllers.NewShortLinkController(
+		shortLinkService,
+		domainService,
+		unlockService,
+		handoffService,
+		urlPolicy,
+		visitService,
+		metadataService,
+		countryResolver,
+		validator,
+		authCfg.AllowAnonymousCreate,
+	)
+
+	return shortLinkController,
+		controllers.NewAnalyticsController(shortLinkService, visitService),
+		controllers.NewQRCodeController(
+			shortLinkService, domainService, qrCodeService, qrCodeCfg.CacheTTL, shortLinkController.Redirect,
+		),
+		controllers.NewAPIKeyController(apiKeyService, validator, authCfg.AdminToken),
+		controllers.NewDomainController(domainService, validator, authCfg.AdminToken)
 }
 
 func initServer(
 	cfg config.HTTPConfig,
+	adminToken string,
+	apiKeyService apikey.APIKeyService,
 	shortLinkController *controllers.ShortLinkController,
 	analyticsController *controllers.AnalyticsController,
+	qrCodeController *controllers.QRCodeController,
+	apiKeyController *controllers.APIKeyController,
+	domainController *controllers.DomainController,
 ) *http.Server {
 	r := chi.NewRouter()
 
@@ -163,8 +345,15 @@ func initServer(
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Recoverer)
 
-	shortLinkController.UseHandlers(r)
-	analyticsController.UseHandlers(r)
+	r.Group(func(r chi.Router) {
+		r.Use(auth.Authenticate(apiKeyService, adminToken))
+
+		shortLinkController.UseHandlers(r)
+		analyticsController.UseHandlers(r)
+		qrCodeController.UseHandlers(r)
+	})
+	apiKeyController.UseHandlers(r)
+	domainController.UseHandlers(r)
 	public.UseStaticFiles(r)
 
 	r.Get("/swagger/*", httpSwagger.WrapHandler)
@@ -181,6 +370,7 @@ func gracefulShutdown(
 	cancelFunc context.CancelFunc,
 	server *http.Server,
 	consumer *kafka.VisitConsumer,
+	metadataConsumer *kafka.MetadataConsumer,
 	redisClient *redis.Client,
 	db *dbpg.DB,
 ) {
@@ -228,5 +418,13 @@ func gracefulShutdown(
 		logger.Info("consumer stopped")
 	})
 
+	wg.Go(func() {
+		if err := metadataConsumer.Stop(); err != nil {
+			logger.Error("failed to close metadata consumer", "err", err)
+		}
+
+		logger.Info("metadata consumer stopped")
+	})
+
 	wg.Wait()
 }
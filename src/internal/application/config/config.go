@@ -1,18 +1,28 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	shortlink "shortener/src/internal/domain/short_link"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
-	LogLevel string `env:"LOG_LEVEL" env-default:"info"`
-	Postgres PostgresConfig
-	HTTP     HTTPConfig
-	Kafka    KafkaConfig
-	Redis    RedisConfig
+	LogLevel  string `env:"LOG_LEVEL" env-default:"info"`
+	Postgres  PostgresConfig
+	HTTP      HTTPConfig
+	Kafka     KafkaConfig
+	Redis     RedisConfig
+	Unlock    UnlockConfig
+	Handoff   HandoffConfig
+	Auth      AuthConfig
+	ShortCode ShortCodeConfig
+	URLPolicy URLPolicyConfig
+	GeoIP     GeoIPConfig
+	QRCode    QRCodeConfig
+	Metadata  MetadataConfig
 }
 
 type PostgresConfig struct {
@@ -26,6 +36,9 @@ type KafkaConfig struct {
 	Broker  string `env:"KAFKA_BROKER" env-default:"kafka:9092"`
 	Topic   string `env:"KAFKA_TOPIC" env-default:"visits"`
 	GroupID string `env:"KAFKA_GROUP_ID" env-default:"visits-group"`
+
+	MetadataTopic   string `env:"KAFKA_METADATA_TOPIC" env-default:"link-metadata"`
+	MetadataGroupID string `env:"KAFKA_METADATA_GROUP_ID" env-default:"link-metadata-group"`
 }
 
 type HTTPConfig struct {
@@ -38,11 +51,125 @@ type RedisConfig struct {
 	DB       int    `env:"REDIS_DB" env-default:"0"`
 }
 
+type UnlockConfig struct {
+	Secret         string        `env:"UNLOCK_SECRET" env-default:""`
+	CookieTTL      time.Duration `env:"UNLOCK_COOKIE_TTL" env-default:"30m"`
+	MaxAttempts    int64         `env:"UNLOCK_MAX_ATTEMPTS" env-default:"5"`
+	AttemptsWindow time.Duration `env:"UNLOCK_ATTEMPTS_WINDOW" env-default:"15m"`
+}
+
+type HandoffConfig struct {
+	Secret   string        `env:"HANDOFF_SECRET" env-default:""`
+	TokenTTL time.Duration `env:"HANDOFF_TOKEN_TTL" env-default:"5m"`
+}
+
+type AuthConfig struct {
+	AdminToken           string `env:"ADMIN_TOKEN" env-default:""`
+	AllowAnonymousCreate bool   `env:"ALLOW_ANONYMOUS_CREATE" env-default:"true"`
+}
+
+type URLPolicyConfig struct {
+	AllowedSchemes      []string      `env:"TARGET_ALLOWED_SCHEMES" env-separator:"," env-default:"http,https"`
+	PublicHosts         []string      `env:"PUBLIC_HOSTS" env-separator:"," env-default:"localhost"`
+	ListsFile           string        `env:"URL_LISTS_FILE" env-default:""`
+	ListsReloadInterval time.Duration `env:"URL_LISTS_RELOAD_INTERVAL" env-default:"30s"`
+	StripTrackingParams bool          `env:"STRIP_TRACKING_PARAMS" env-default:"false"`
+}
+
+// GeoIPConfig lists the headers carrying the visitor country; a proxy in front of the service has to set them.
+type GeoIPConfig struct {
+	CountryHeaders []string `env:"GEOIP_COUNTRY_HEADERS" env-separator:"," env-default:"CF-IPCountry"`
+}
+
+type QRCodeConfig struct {
+	CacheTTL time.Duration `env:"QR_CACHE_TTL" env-default:"24h"`
+}
+
+// MetadataConfig limits fetching target pages. Private and loopback addresses are refused unless
+// AllowPrivateNetworks is set, since the targets come from users.
+type MetadataConfig struct {
+	Timeout              time.Duration `env:"METADATA_FETCH_TIMEOUT" env-default:"5s"`
+	MaxBodySize          int64         `env:"METADATA_MAX_BODY_SIZE" env-default:"1048576"`
+	UserAgent            string        `env:"METADATA_USER_AGENT" env-default:"ShortenerBot/1.0"`
+	AllowPrivateNetworks bool          `env:"METADATA_ALLOW_PRIVATE_NETWORKS" env-default:"false"`
+	Workers              int           `env:"METADATA_WORKERS" env-default:"4"`
+}
+
+const (
+	ShortCodeGeneratorRandom     = "random"
+	ShortCodeGeneratorSequential = "sequential"
+	ShortCodeGeneratorHash       = "hash"
+)
+
+type ShortCodeConfig struct {
+	Generator      string   `env:"SHORT_CODE_GENERATOR" env-default:"random"`
+	Key            string   `env:"SHORT_CODE_KEY" env-default:""`
+	BlockSize      int64    `env:"SHORT_CODE_BLOCK_SIZE" env-default:"1000"`
+	Length         int      `env:"SHORT_CODE_LENGTH" env-default:"6"`
+	Alphabet       string   `env:"SHORT_CODE_ALPHABET" env-default:"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"`
+	AliasMinLength int      `env:"CUSTOM_ALIAS_MIN_LENGTH" env-default:"1"`
+	AliasMaxLength int      `env:"CUSTOM_ALIAS_MAX_LENGTH" env-default:"32"`
+	ReservedWords  []string `env:"SHORT_CODE_RESERVED_WORDS" env-separator:"," env-default:"admin,analytics,api,api-keys,domains,handoff,links,s,shorten,static,swagger"`
+}
+
 func Load() (*Config, error) {
 	var cfg Config
 	if err := cleanenv.ReadEnv(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
+	if err := cfg.ShortCode.validate(); err != nil {
+		return nil, fmt.Errorf("invalid short code config: %w", err)
+	}
+
 	return &cfg, nil
 }
+
+func (c ShortCodeConfig) validate() error {
+	switch c.Generator {
+	case ShortCodeGeneratorRandom:
+	case ShortCodeGeneratorSequential:
+		if c.Key == "" {
+			return errors.New("SHORT_CODE_KEY is required for the sequential generator")
+		}
+
+		if c.BlockSize < 1 {
+			return errors.New("SHORT_CODE_BLOCK_SIZE must be positive")
+		}
+	case ShortCodeGeneratorHash:
+		if c.Key == "" {
+			return errors.New("SHORT_CODE_KEY is required for the hash generator")
+		}
+	default:
+		return fmt.Errorf("unknown SHORT_CODE_GENERATOR %q, expected random, sequential or hash", c.Generator)
+	}
+
+	if c.Length < 1 || c.Length > shortlink.MaxShortCodeLength {
+		return fmt.Errorf("SHORT_CODE_LENGTH must be between 1 and %d", shortlink.MaxShortCodeLength)
+	}
+
+	if c.AliasMinLength < 1 || c.AliasMinLength > c.AliasMaxLength || c.AliasMaxLength > shortlink.MaxShortCodeLength {
+		return fmt.Errorf(
+			"custom alias lengths must satisfy 1 <= CUSTOM_ALIAS_MIN_LENGTH <= CUSTOM_ALIAS_MAX_LENGTH <= %d",
+			shortlink.MaxShortCodeLength,
+		)
+	}
+
+	if len(c.Alphabet) < 2 {
+		return errors.New("SHORT_CODE_ALPHABET must contain at least 2 characters")
+	}
+
+	seen := make(map[rune]bool, len(c.Alphabet))
+	for _, ch := range c.Alphabet {
+		if !shortlink.IsShortCodeChar(ch) {
+			return fmt.Errorf("SHORT_CODE_ALPHABET contains %q, only letters, digits, '-' and '_' are allowed", ch)
+		}
+
+		if seen[ch] {
+			return fmt.Errorf("SHORT_CODE_ALPHABET contains %q twice", ch)
+		}
+		seen[ch] = true
+	}
+
+	return nil
+}
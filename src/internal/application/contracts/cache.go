@@ -8,4 +8,7 @@ import (
 type Cache interface {
 	Set(ctx context.Context, key string, value any, expiration time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
+	Increment(ctx context.Context, key string, expiration time.Duration) (int64, error)
+	Decrement(ctx context.Context, key string) error
+	Delete(ctx context.Context, key string) error
 }
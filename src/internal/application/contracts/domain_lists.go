@@ -0,0 +1,6 @@
+package contracts
+
+type DomainLists interface {
+	// Lists returns the current allowlist and blocklist; an empty allowlist allows every domain.
+	Lists() (allow, block []string)
+}
@@ -2,6 +2,12 @@ package contracts
 
 import "context"
 
+type Message struct {
+	Key   []byte
+	Value []byte
+}
+
 type MessageProducer interface {
 	Produce(ctx context.Context, key, value []byte) error
+	ProduceBatch(ctx context.Context, messages []Message) error
 }
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	apikey "shortener/src/internal/domain/api_key"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+)
+
+type APIKeyService struct {
+	apiKeyRepository apikey.APIKeyRepository
+}
+
+func NewAPIKeyService(apiKeyRepository apikey.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepository: apiKeyRepository,
+	}
+}
+
+func (s *APIKeyService) Issue(
+	ctx context.Context,
+	ownerID *uuid.UUID,
+	name string,
+) (*apikey.APIKey, string, error) {
+	prefix, err := randomBytes(apiKeyPrefixBytes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret, err := randomBytes(apiKeySecretBytes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := apikey.APIKey{
+		ID:        uuid.New(),
+		Name:      name,
+		Prefix:    hex.EncodeToString(prefix),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	encodedSecret := base64.RawURLEncoding.EncodeToString(secret)
+	key.SecretHash = hashSecret(encodedSecret)
+
+	key.OwnerID = uuid.New()
+	if ownerID != nil {
+		key.OwnerID = *ownerID
+	}
+
+	if err := s.apiKeyRepository.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	return &key, key.Prefix + "." + encodedSecret, nil
+}
+
+func (s *APIKeyService) Authenticate(ctx context.Context, token string) (*apikey.APIKey, error) {
+	prefix, secret, ok := strings.Cut(token, ".")
+	if !ok || prefix == "" || secret == "" {
+		return nil, apikey.ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepository.GetByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, apikey.ErrAPIKeyNotFound) {
+			return nil, apikey.ErrInvalidAPIKey
+		}
+
+		return nil, err
+	}
+
+	if key.Revoked() || subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(hashSecret(secret))) != 1 {
+		return nil, apikey.ErrInvalidAPIKey
+	}
+
+	return key, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id uuid.UUID) error {
+	return s.apiKeyRepository.Revoke(ctx, id)
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("read random: %w", err)
+	}
+
+	return b, nil
+}
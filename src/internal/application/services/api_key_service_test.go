@@ -0,0 +1,106 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"shortener/src/internal/application/services"
+	"shortener/src/internal/application/services/mocks"
+	apikey "shortener/src/internal/domain/api_key"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyService_IssueThenAuthenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+
+	var stored apikey.APIKey
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key apikey.APIKey) error {
+			stored = key
+			return nil
+		})
+
+	svc := services.NewAPIKeyService(mockRepo)
+
+	owner := uuid.New()
+	key, token, err := svc.Issue(context.Background(), &owner, "ci")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key.OwnerID != owner {
+		t.Fatalf("expected owner %s, got %s", owner, key.OwnerID)
+	}
+	if strings.Contains(token, stored.SecretHash) || !strings.HasPrefix(token, stored.Prefix+".") {
+		t.Fatalf("unexpected token %q for stored key %+v", token, stored)
+	}
+
+	mockRepo.EXPECT().GetByPrefix(gomock.Any(), gomock.Eq(stored.Prefix)).Return(&stored, nil)
+
+	got, err := svc.Authenticate(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != key.ID {
+		t.Fatalf("expected key %s, got %s", key.ID, got.ID)
+	}
+}
+
+func TestAPIKeyService_Authenticate_WrongSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockRepo.EXPECT().GetByPrefix(gomock.Any(), gomock.Eq("abc")).
+		Return(&apikey.APIKey{Prefix: "abc", SecretHash: "hash"}, nil)
+
+	svc := services.NewAPIKeyService(mockRepo)
+	if _, err := svc.Authenticate(context.Background(), "abc.secret"); !errors.Is(err, apikey.ErrInvalidAPIKey) {
+		t.Fatalf("expected ErrInvalidAPIKey, got: %v", err)
+	}
+}
+
+func TestAPIKeyService_Authenticate_Revoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+
+	var stored apikey.APIKey
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key apikey.APIKey) error {
+			stored = key
+			return nil
+		})
+
+	svc := services.NewAPIKeyService(mockRepo)
+	_, token, err := svc.Issue(context.Background(), nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	revokedAt := time.Now()
+	stored.RevokedAt = &revokedAt
+	mockRepo.EXPECT().GetByPrefix(gomock.Any(), gomock.Any()).Return(&stored, nil)
+
+	if _, err := svc.Authenticate(context.Background(), token); !errors.Is(err, apikey.ErrInvalidAPIKey) {
+		t.Fatalf("expected ErrInvalidAPIKey, got: %v", err)
+	}
+}
+
+func TestAPIKeyService_Authenticate_Malformed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := services.NewAPIKeyService(mocks.NewMockAPIKeyRepository(ctrl))
+	if _, err := svc.Authenticate(context.Background(), "no-dot"); !errors.Is(err, apikey.ErrInvalidAPIKey) {
+		t.Fatalf("expected ErrInvalidAPIKey, got: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"shortener/src/internal/application/contracts"
+	brandeddomain "shortener/src/internal/domain/branded_domain"
+	"shortener/src/pkg/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	domainKeyPrefix   = "domain:"
+	resolvedDomainTTL = time.Minute * 5
+	registeredDomain  = "1"
+	unknownDomainTTL  = time.Minute
+	maxUnknownDomains = 10000
+)
+
+// DomainService resolves Host headers to branded domains. Registered domains are cached in Redis; unknown
+// hosts are remembered only in process and in bounded number, so arbitrary Host headers cannot fill Redis.
+type DomainService struct {
+	domainRepository brandeddomain.DomainRepository
+	cache            contracts.Cache
+	publicHosts      map[string]bool
+	unknown          *unknownHosts
+}
+
+// NewDomainService resolves the service's own publicHosts to the default domain without any lookup.
+func NewDomainService(
+	domainRepository brandeddomain.DomainRepository,
+	cache contracts.Cache,
+	publicHosts []string,
+) *DomainService {
+	service := &DomainService{
+		domainRepository: domainRepository,
+		cache:            cache,
+		publicHosts:      make(map[string]bool, len(publicHosts)),
+		unknown:          &unknownHosts{expires: make(map[string]time.Time)},
+	}
+
+	for _, host := range publicHosts {
+		service.publicHosts[normalizeHost(host)] = true
+	}
+
+	return service
+}
+
+func (s *DomainService) Register(ctx context.Context, host string) (*brandeddomain.Domain, error) {
+	host = normalizeHost(host)
+
+	domain, err := s.domainRepository.Create(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	s.evictCache(ctx, host)
+
+	return domain, nil
+}
+
+func (s *DomainService) List(ctx context.Context) ([]brandeddomain.Domain, error) {
+	return s.domainRepository.List(ctx)
+}
+
+func (s *DomainService) Delete(ctx context.Context, host string) error {
+	host = normalizeHost(host)
+
+	if err := s.domainRepository.Delete(ctx, host); err != nil {
+		return err
+	}
+
+	s.evictCache(ctx, host)
+
+	return nil
+}
+
+func (s *DomainService) Resolve(ctx context.Context, host string) (string, error) {
+	host = normalizeHost(host)
+	if host == "" || s.publicHosts[host] || s.unknown.contains(host, time.Now()) {
+		return "", nil
+	}
+
+	value, err := s.cache.Get(ctx, domainKeyPrefix+host)
+	if err == nil && value == registeredDomain {
+		return host, nil
+	}
+
+	_, err = s.domainRepository.Get(ctx, host)
+	if err != nil {
+		if !errors.Is(err, brandeddomain.ErrDomainNotFound) {
+			return "", err
+		}
+
+		s.unknown.add(host, time.Now())
+		return "", nil
+	}
+
+	if err := s.cache.Set(ctx, domainKeyPrefix+host, registeredDomain, resolvedDomainTTL); err != nil {
+		logger.Error("failed to set domain into cache", "err", err)
+	}
+
+	return host, nil
+}
+
+func (s *DomainService) evictCache(ctx context.Context, host string) {
+	s.unknown.remove(host)
+
+	if err := s.cache.Delete(ctx, domainKeyPrefix+host); err != nil {
+		logger.Error("failed to evict domain from cache", "err", err)
+	}
+}
+
+// unknownHosts is the negative cache of Resolve. Other instances do not see Register, so entries live
+// only unknownDomainTTL.
+type unknownHosts struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+}
+
+func (h *unknownHosts) contains(host string, now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	expires, ok := h.expires[host]
+	return ok && now.Before(expires)
+}
+
+func (h *unknownHosts) add(host string, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.expires) >= maxUnknownDomains {
+		for known, expires := range h.expires {
+			if !now.Before(expires) {
+				delete(h.expires, known)
+			}
+		}
+	}
+
+	// Still full: forget an arbitrary host rather than grow.
+	if len(h.expires) >= maxUnknownDomains {
+		for known := range h.expires {
+			delete(h.expires, known)
+			break
+		}
+	}
+
+	h.expires[host] = now.Add(unknownDomainTTL)
+}
+
+func (h *unknownHosts) remove(host string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.expires, host)
+}
+
+func normalizeHost(host string) string {
+	host = strings.TrimSpace(host)
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
@@ -0,0 +1,132 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"shortener/src/internal/application/services"
+	"shortener/src/internal/application/services/mocks"
+	brandeddomain "shortener/src/internal/domain/branded_domain"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestDomainService_Resolve_CacheHit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDomainRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Eq("domain:go.brand.com")).Return("1", nil)
+
+	svc := services.NewDomainService(mockRepo, mockCache, []string{"sho.rt"})
+	domain, err := svc.Resolve(context.Background(), "Go.Brand.com:8080")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if domain != "go.brand.com" {
+		t.Fatalf("expected go.brand.com, got %q", domain)
+	}
+}
+
+func TestDomainService_Resolve_UnknownHostIsDefaultAndCachedInProcess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDomainRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Eq("domain:unknown.com")).Return("", errors.New("not found"))
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq("unknown.com")).Return(nil, brandeddomain.ErrDomainNotFound)
+
+	svc := services.NewDomainService(mockRepo, mockCache, []string{"sho.rt"})
+	for range 2 {
+		domain, err := svc.Resolve(context.Background(), "unknown.com:8080")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if domain != "" {
+			t.Fatalf("expected default domain, got %q", domain)
+		}
+	}
+}
+
+func TestDomainService_Resolve_PublicHostSkipsLookup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := services.NewDomainService(mocks.NewMockDomainRepository(ctrl), mocks.NewMockCache(ctrl), []string{"sho.rt"})
+	domain, err := svc.Resolve(context.Background(), "SHO.RT:443")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if domain != "" {
+		t.Fatalf("expected default domain, got %q", domain)
+	}
+}
+
+func TestDomainService_Register_ForgetsUnknownHost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDomainRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Eq("domain:go.brand.com")).Return("", errors.New("not found")).Times(2)
+	mockCache.EXPECT().Delete(gomock.Any(), gomock.Eq("domain:go.brand.com")).Return(nil)
+	mockCache.EXPECT().Set(gomock.Any(), gomock.Eq("domain:go.brand.com"), gomock.Eq("1"), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Eq("go.brand.com")).
+		Return(&brandeddomain.Domain{Host: "go.brand.com"}, nil)
+	first := mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq("go.brand.com")).Return(nil, brandeddomain.ErrDomainNotFound)
+	second := mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq("go.brand.com")).
+		Return(&brandeddomain.Domain{Host: "go.brand.com"}, nil)
+	gomock.InOrder(first, second)
+
+	svc := services.NewDomainService(mockRepo, mockCache, []string{"sho.rt"})
+	if domain, err := svc.Resolve(context.Background(), "go.brand.com"); err != nil || domain != "" {
+		t.Fatalf("expected default domain, got %q, %v", domain, err)
+	}
+	if _, err := svc.Register(context.Background(), "go.brand.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if domain, err := svc.Resolve(context.Background(), "go.brand.com"); err != nil || domain != "go.brand.com" {
+		t.Fatalf("expected go.brand.com, got %q, %v", domain, err)
+	}
+}
+
+func TestDomainService_Resolve_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDomainRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	dbErr := errors.New("db down")
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Any()).Return("", errors.New("not found"))
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, dbErr)
+
+	svc := services.NewDomainService(mockRepo, mockCache, []string{"sho.rt"})
+	if _, err := svc.Resolve(context.Background(), "go.brand.com"); !errors.Is(err, dbErr) {
+		t.Fatalf("expected db error, got %v", err)
+	}
+}
+
+func TestDomainService_Register_EvictsCachedState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDomainRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	create := mockRepo.EXPECT().Create(gomock.Any(), gomock.Eq("go.brand.com")).
+		Return(&brandeddomain.Domain{Host: "go.brand.com"}, nil)
+	evict := mockCache.EXPECT().Delete(gomock.Any(), gomock.Eq("domain:go.brand.com")).Return(nil)
+	gomock.InOrder(create, evict)
+
+	svc := services.NewDomainService(mockRepo, mockCache, []string{"sho.rt"})
+	if _, err := svc.Register(context.Background(), "GO.brand.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
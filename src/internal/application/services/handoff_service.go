@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"shortener/src/internal/application/config"
+	"shortener/src/internal/application/contracts"
+	shortlink "shortener/src/internal/domain/short_link"
+	"shortener/src/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const handoffTokenKeyPrefix = "handoff:"
+
+// HandoffService signs the report of a handoff page. The page is rendered only after the click was taken
+// from the link's budget, so a token stands for that click and is redeemed at most once.
+type HandoffService struct {
+	cache    contracts.Cache
+	secret   []byte
+	tokenTTL time.Duration
+}
+
+func NewHandoffService(cache contracts.Cache, cfg config.HandoffConfig) (*HandoffService, error) {
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("generate handoff secret: %w", err)
+		}
+		logger.Info("HANDOFF_SECRET is not set, handoff pages will not survive restarts")
+	}
+
+	return &HandoffService{
+		cache:    cache,
+		secret:   secret,
+		tokenTTL: cfg.TokenTTL,
+	}, nil
+}
+
+func (s *HandoffService) Issue(link *shortlink.ShortLink, report shortlink.HandoffReport) (string, error) {
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", fmt.Errorf("generate handoff nonce: %w", err)
+	}
+
+	id := base64.RawURLEncoding.EncodeToString(nonce[:])
+	expires := strconv.FormatInt(time.Now().Add(s.tokenTTL).Unix(), 10)
+
+	return id + "." + expires + "." + s.sign(link, report, id, expires), nil
+}
+
+func (s *HandoffService) Redeem(
+	ctx context.Context,
+	link *shortlink.ShortLink,
+	report shortlink.HandoffReport,
+	token string,
+) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	id, expires, signature := parts[0], parts[1], parts[2]
+
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() >= expiresUnix {
+		return false
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(link, report, id, expires))) {
+		return false
+	}
+
+	redeemed, err := s.cache.Increment(ctx, handoffTokenKeyPrefix+id, s.tokenTTL)
+	if err != nil {
+		logger.Error("failed to redeem handoff token", "err", err)
+		return true
+	}
+
+	return redeemed == 1
+}
+
+func (s *HandoffService) sign(link *shortlink.ShortLink, report shortlink.HandoffReport, id, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	for _, part := range []string{link.ID.String(), report.Rule, report.Variant, report.Source, id, expires} {
+		mac.Write([]byte(part))
+		mac.Write([]byte{0})
+	}
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"shortener/src/internal/application/config"
+	"shortener/src/internal/application/services"
+	"shortener/src/internal/application/services/mocks"
+	shortlink "shortener/src/internal/domain/short_link"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+var testHandoffConfig = config.HandoffConfig{
+	Secret:   "secret",
+	TokenTTL: time.Minute,
+}
+
+func TestHandoffService_Redeem_TokenIsSingleUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	first := mockCache.EXPECT().Increment(gomock.Any(), gomock.Any(), time.Minute).Return(int64(1), nil)
+	second := mockCache.EXPECT().Increment(gomock.Any(), gomock.Any(), time.Minute).Return(int64(2), nil)
+	gomock.InOrder(first, second)
+
+	svc, err := services.NewHandoffService(mockCache, testHandoffConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	link := &shortlink.ShortLink{ID: uuid.New()}
+	report := shortlink.HandoffReport{Rule: "ios", Source: "qr"}
+	token, err := svc.Issue(link, report)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !svc.Redeem(context.Background(), link, report, token) {
+		t.Fatalf("expected token to be redeemed")
+	}
+	if svc.Redeem(context.Background(), link, report, token) {
+		t.Fatalf("expected token to be redeemed only once")
+	}
+}
+
+func TestHandoffService_Redeem_RejectsOtherLinkOrReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, err := services.NewHandoffService(mocks.NewMockCache(ctrl), testHandoffConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	link := &shortlink.ShortLink{ID: uuid.New()}
+	report := shortlink.HandoffReport{Rule: "ios", Variant: "a"}
+	token, err := svc.Issue(link, report)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := map[string]struct {
+		link   *shortlink.ShortLink
+		report shortlink.HandoffReport
+		token  string
+	}{
+		"other link":    {&shortlink.ShortLink{ID: uuid.New()}, report, token},
+		"other rule":    {link, shortlink.HandoffReport{Rule: "android", Variant: "a"}, token},
+		"other variant": {link, shortlink.HandoffReport{Rule: "ios", Variant: "b"}, token},
+		"other source":  {link, shortlink.HandoffReport{Rule: "ios", Variant: "a", Source: "qr"}, token},
+		"no token":      {link, report, ""},
+		"forged token":  {link, report, "id.9999999999.signature"},
+	}
+	for name, tc := range cases {
+		if svc.Redeem(context.Background(), tc.link, tc.report, tc.token) {
+			t.Fatalf("%s: expected token to be rejected", name)
+		}
+	}
+}
+
+func TestHandoffService_Redeem_ExpiredToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, err := services.NewHandoffService(mocks.NewMockCache(ctrl), config.HandoffConfig{Secret: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	link := &shortlink.ShortLink{ID: uuid.New()}
+	token, err := svc.Issue(link, shortlink.HandoffReport{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if svc.Redeem(context.Background(), link, shortlink.HandoffReport{}, token) {
+		t.Fatalf("expected expired token to be rejected")
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"shortener/src/internal/application/contracts"
+	shortlink "shortener/src/internal/domain/short_link"
+	"shortener/src/pkg/logger"
+)
+
+type MetadataService struct {
+	shortLinkRepository shortlink.ShortLinkRepository
+	fetcher             shortlink.MetadataFetcher
+	urlPolicy           shortlink.URLPolicy
+	producer            contracts.MessageProducer
+	cache               contracts.Cache
+}
+
+func NewMetadataService(
+	shortLinkRepo shortlink.ShortLinkRepository,
+	fetcher shortlink.MetadataFetcher,
+	urlPolicy shortlink.URLPolicy,
+	producer contracts.MessageProducer,
+	cache contracts.Cache,
+) *MetadataService {
+	return &MetadataService{
+		shortLinkRepository: shortLinkRepo,
+		fetcher:             fetcher,
+		urlPolicy:           urlPolicy,
+		producer:            producer,
+		cache:               cache,
+	}
+}
+
+func (s *MetadataService) Request(ctx context.Context, links ...shortlink.ShortLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	messages := make([]contracts.Message, len(links))
+	for i, link := range links {
+		bytes, err := json.Marshal(shortlink.MetadataRequest{
+			LinkID:    link.ID,
+			Domain:    link.Domain,
+			ShortCode: link.ShortCode,
+			URL:       link.OriginalURL,
+		})
+		if err != nil {
+			return err
+		}
+
+		messages[i] = contracts.Message{Key: []byte(link.ID.String()), Value: bytes}
+	}
+
+	return s.producer.ProduceBatch(ctx, messages)
+}
+
+// Fetch checks the target against the URL policy again, since it may have changed while the request was queued.
+func (s *MetadataService) Fetch(ctx context.Context, req shortlink.MetadataRequest) error {
+	if err := s.urlPolicy.Check(ctx, req.URL); err != nil {
+		return err
+	}
+
+	metadata, err := s.fetcher.Fetch(ctx, req.URL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.shortLinkRepository.SetMetadata(ctx, req.LinkID, req.URL, *metadata); err != nil {
+		return err
+	}
+
+	if err := s.cache.Delete(ctx, cacheKey(req.Domain, req.ShortCode)); err != nil {
+		logger.Error("failed to evict URL from cache", "err", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"shortener/src/internal/application/contracts"
+	"shortener/src/internal/application/services"
+	"shortener/src/internal/application/services/mocks"
+	shortlink "shortener/src/internal/domain/short_link"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+type stubFetcher struct {
+	metadata *shortlink.Metadata
+	fetched  []string
+}
+
+func (f *stubFetcher) Fetch(_ context.Context, targetURL string) (*shortlink.Metadata, error) {
+	f.fetched = append(f.fetched, targetURL)
+	return f.metadata, nil
+}
+
+func TestMetadataService_Request(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProducer := mocks.NewMockMessageProducer(ctrl)
+
+	link := shortlink.ShortLink{ID: uuid.New(), Domain: "go.brand.com", ShortCode: "k", OriginalURL: "https://example.com"}
+	mockProducer.EXPECT().ProduceBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, messages []contracts.Message) error {
+			if len(messages) != 1 || string(messages[0].Key) != link.ID.String() {
+				t.Fatalf("expected one message keyed by the link ID, got %+v", messages)
+			}
+
+			var req shortlink.MetadataRequest
+			if err := json.Unmarshal(messages[0].Value, &req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := shortlink.MetadataRequest{LinkID: link.ID, Domain: "go.brand.com", ShortCode: "k", URL: link.OriginalURL}
+			if req != want {
+				t.Fatalf("got request %+v, want %+v", req, want)
+			}
+
+			return nil
+		})
+
+	svc := services.NewMetadataService(nil, &stubFetcher{}, allowAllURLs{}, mockProducer, nil)
+	if err := svc.Request(context.Background(), link); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetadataService_Fetch_StoresAndEvictsCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	id := uuid.New()
+	metadata := &shortlink.Metadata{Title: "Example"}
+	store := mockRepo.EXPECT().
+		SetMetadata(gomock.Any(), gomock.Eq(id), gomock.Eq("https://example.com"), gomock.Eq(*metadata)).
+		Return(nil)
+	evict := mockCache.EXPECT().Delete(gomock.Any(), gomock.Eq("/k")).Return(nil)
+	gomock.InOrder(store, evict)
+
+	svc := services.NewMetadataService(mockRepo, &stubFetcher{metadata: metadata}, allowAllURLs{}, nil, mockCache)
+	req := shortlink.MetadataRequest{LinkID: id, ShortCode: "k", URL: "https://example.com"}
+	if err := svc.Fetch(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetadataService_Fetch_TargetNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fetcher := &stubFetcher{}
+	svc := services.NewMetadataService(mocks.NewMockShortLinkRepository(ctrl), fetcher, rejectURLs{}, nil, nil)
+
+	req := shortlink.MetadataRequest{LinkID: uuid.New(), ShortCode: "k", URL: "https://blocked.example"}
+	if err := svc.Fetch(context.Background(), req); !errors.Is(err, shortlink.ErrTargetNotAllowed) {
+		t.Fatalf("expected ErrTargetNotAllowed, got: %v", err)
+	}
+
+	if len(fetcher.fetched) != 0 {
+		t.Fatalf("expected no fetch of a blocked target, got %v", fetcher.fetched)
+	}
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/internal/domain/branded_domain/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/internal/domain/branded_domain/repository.go -package=mocks -destination=src/internal/application/services/mocks/domain_repository.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	branded_domain "shortener/src/internal/domain/branded_domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainRepository is a mock of DomainRepository interface.
+type MockDomainRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDomainRepositoryMockRecorder is the mock recorder for MockDomainRepository.
+type MockDomainRepositoryMockRecorder struct {
+	mock *MockDomainRepository
+}
+
+// NewMockDomainRepository creates a new mock instance.
+func NewMockDomainRepository(ctrl *gomock.Controller) *MockDomainRepository {
+	mock := &MockDomainRepository{ctrl: ctrl}
+	mock.recorder = &MockDomainRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainRepository) EXPECT() *MockDomainRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDomainRepository) Create(ctx context.Context, host string) (*branded_domain.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, host)
+	ret0, _ := ret[0].(*branded_domain.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDomainRepositoryMockRecorder) Create(ctx, host any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDomainRepository)(nil).Create), ctx, host)
+}
+
+// Delete mocks base method.
+func (m *MockDomainRepository) Delete(ctx context.Context, host string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, host)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDomainRepositoryMockRecorder) Delete(ctx, host any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDomainRepository)(nil).Delete), ctx, host)
+}
+
+// Get mocks base method.
+func (m *MockDomainRepository) Get(ctx context.Context, host string) (*branded_domain.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, host)
+	ret0, _ := ret[0].(*branded_domain.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDomainRepositoryMockRecorder) Get(ctx, host any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDomainRepository)(nil).Get), ctx, host)
+}
+
+// List mocks base method.
+func (m *MockDomainRepository) List(ctx context.Context) ([]branded_domain.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]branded_domain.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDomainRepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDomainRepository)(nil).List), ctx)
+}
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	contracts "shortener/src/internal/application/contracts"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -53,3 +54,17 @@ func (mr *MockMessageProducerMockRecorder) Produce(ctx, key, value any) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Produce", reflect.TypeOf((*MockMessageProducer)(nil).Produce), ctx, key, value)
 }
+
+// ProduceBatch mocks base method.
+func (m *MockMessageProducer) ProduceBatch(ctx context.Context, messages []contracts.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceBatch", ctx, messages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceBatch indicates an expected call of ProduceBatch.
+func (mr *MockMessageProducerMockRecorder) ProduceBatch(ctx, messages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceBatch", reflect.TypeOf((*MockMessageProducer)(nil).ProduceBatch), ctx, messages)
+}
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 	short_link "shortener/src/internal/domain/short_link"
 
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,32 +42,206 @@ func (m *MockShortLinkRepository) EXPECT() *MockShortLinkRepositoryMockRecorder
 	return m.recorder
 }
 
+// AttachTags mocks base method.
+func (m *MockShortLinkRepository) AttachTags(ctx context.Context, linkID, ownerID uuid.UUID, tags []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachTags", ctx, linkID, ownerID, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachTags indicates an expected call of AttachTags.
+func (mr *MockShortLinkRepositoryMockRecorder) AttachTags(ctx, linkID, ownerID, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachTags", reflect.TypeOf((*MockShortLinkRepository)(nil).AttachTags), ctx, linkID, ownerID, tags)
+}
+
+// AvailableCodes mocks base method.
+func (m *MockShortLinkRepository) AvailableCodes(ctx context.Context, domain string, codes []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AvailableCodes", ctx, domain, codes)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AvailableCodes indicates an expected call of AvailableCodes.
+func (mr *MockShortLinkRepositoryMockRecorder) AvailableCodes(ctx, domain, codes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AvailableCodes", reflect.TypeOf((*MockShortLinkRepository)(nil).AvailableCodes), ctx, domain, codes)
+}
+
+// ConsumeClick mocks base method.
+func (m *MockShortLinkRepository) ConsumeClick(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeClick", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeClick indicates an expected call of ConsumeClick.
+func (mr *MockShortLinkRepositoryMockRecorder) ConsumeClick(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeClick", reflect.TypeOf((*MockShortLinkRepository)(nil).ConsumeClick), ctx, id)
+}
+
 // Create mocks base method.
-func (m *MockShortLinkRepository) Create(ctx context.Context, shortURL, originalURL string) (*short_link.ShortLink, error) {
+func (m *MockShortLinkRepository) Create(ctx context.Context, params short_link.CreateParams) (*short_link.ShortLink, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, shortURL, originalURL)
+	ret := m.ctrl.Call(m, "Create", ctx, params)
 	ret0, _ := ret[0].(*short_link.ShortLink)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockShortLinkRepositoryMockRecorder) Create(ctx, shortURL, originalURL any) *gomock.Call {
+func (mr *MockShortLinkRepositoryMockRecorder) Create(ctx, params any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockShortLinkRepository)(nil).Create), ctx, shortURL, originalURL)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockShortLinkRepository)(nil).Create), ctx, params)
+}
+
+// CreateBatch mocks base method.
+func (m *MockShortLinkRepository) CreateBatch(ctx context.Context, params []short_link.CreateParams) ([]short_link.CreateResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, params)
+	ret0, _ := ret[0].([]short_link.CreateResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockShortLinkRepositoryMockRecorder) CreateBatch(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockShortLinkRepository)(nil).CreateBatch), ctx, params)
+}
+
+// Delete mocks base method.
+func (m *MockShortLinkRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockShortLinkRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockShortLinkRepository)(nil).Delete), ctx, id)
+}
+
+// DetachTag mocks base method.
+func (m *MockShortLinkRepository) DetachTag(ctx context.Context, linkID, ownerID uuid.UUID, tag string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachTag", ctx, linkID, ownerID, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachTag indicates an expected call of DetachTag.
+func (mr *MockShortLinkRepositoryMockRecorder) DetachTag(ctx, linkID, ownerID, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachTag", reflect.TypeOf((*MockShortLinkRepository)(nil).DetachTag), ctx, linkID, ownerID, tag)
 }
 
 // Get mocks base method.
-func (m *MockShortLinkRepository) Get(ctx context.Context, shortURL string) (*short_link.ShortLink, error) {
+func (m *MockShortLinkRepository) Get(ctx context.Context, domain, shortURL string) (*short_link.ShortLink, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, shortURL)
+	ret := m.ctrl.Call(m, "Get", ctx, domain, shortURL)
 	ret0, _ := ret[0].(*short_link.ShortLink)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockShortLinkRepositoryMockRecorder) Get(ctx, shortURL any) *gomock.Call {
+func (mr *MockShortLinkRepositoryMockRecorder) Get(ctx, domain, shortURL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockShortLinkRepository)(nil).Get), ctx, domain, shortURL)
+}
+
+// GetDeduplicated mocks base method.
+func (m *MockShortLinkRepository) GetDeduplicated(ctx context.Context, ownerID *uuid.UUID, domain, targetHash string) (*short_link.ShortLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeduplicated", ctx, ownerID, domain, targetHash)
+	ret0, _ := ret[0].(*short_link.ShortLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeduplicated indicates an expected call of GetDeduplicated.
+func (mr *MockShortLinkRepositoryMockRecorder) GetDeduplicated(ctx, ownerID, domain, targetHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeduplicated", reflect.TypeOf((*MockShortLinkRepository)(nil).GetDeduplicated), ctx, ownerID, domain, targetHash)
+}
+
+// IDsByTag mocks base method.
+func (m *MockShortLinkRepository) IDsByTag(ctx context.Context, ownerID *uuid.UUID, tag string) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IDsByTag", ctx, ownerID, tag)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IDsByTag indicates an expected call of IDsByTag.
+func (mr *MockShortLinkRepositoryMockRecorder) IDsByTag(ctx, ownerID, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IDsByTag", reflect.TypeOf((*MockShortLinkRepository)(nil).IDsByTag), ctx, ownerID, tag)
+}
+
+// List mocks base method.
+func (m *MockShortLinkRepository) List(ctx context.Context, params short_link.ListParams) (*short_link.ListPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, params)
+	ret0, _ := ret[0].(*short_link.ListPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockShortLinkRepositoryMockRecorder) List(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockShortLinkRepository)(nil).List), ctx, params)
+}
+
+// RetireDeduplicated mocks base method.
+func (m *MockShortLinkRepository) RetireDeduplicated(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireDeduplicated", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetireDeduplicated indicates an expected call of RetireDeduplicated.
+func (mr *MockShortLinkRepositoryMockRecorder) RetireDeduplicated(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireDeduplicated", reflect.TypeOf((*MockShortLinkRepository)(nil).RetireDeduplicated), ctx, id)
+}
+
+// SetMetadata mocks base method.
+func (m *MockShortLinkRepository) SetMetadata(ctx context.Context, id uuid.UUID, targetURL string, metadata short_link.Metadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMetadata", ctx, id, targetURL, metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMetadata indicates an expected call of SetMetadata.
+func (mr *MockShortLinkRepositoryMockRecorder) SetMetadata(ctx, id, targetURL, metadata any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMetadata", reflect.TypeOf((*MockShortLinkRepository)(nil).SetMetadata), ctx, id, targetURL, metadata)
+}
+
+// Update mocks base method.
+func (m *MockShortLinkRepository) Update(ctx context.Context, id uuid.UUID, params short_link.UpdateParams) (*short_link.ShortLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, params)
+	ret0, _ := ret[0].(*short_link.ShortLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockShortLinkRepositoryMockRecorder) Update(ctx, id, params any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockShortLinkRepository)(nil).Get), ctx, shortURL)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockShortLinkRepository)(nil).Update), ctx, id, params)
 }
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 	visit "shortener/src/internal/domain/visit"
 
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,48 +43,98 @@ func (m *MockVisitRepository) EXPECT() *MockVisitRepositoryMockRecorder {
 }
 
 // AnalyticsAggregatedByDay mocks base method.
-func (m *MockVisitRepository) AnalyticsAggregatedByDay(ctx context.Context, shortURL string) ([]visit.PeriodCount, error) {
+func (m *MockVisitRepository) AnalyticsAggregatedByDay(ctx context.Context, linkIDs ...uuid.UUID) ([]visit.PeriodCount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AnalyticsAggregatedByDay", ctx, shortURL)
+	varargs := []any{ctx}
+	for _, a := range linkIDs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AnalyticsAggregatedByDay", varargs...)
 	ret0, _ := ret[0].([]visit.PeriodCount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AnalyticsAggregatedByDay indicates an expected call of AnalyticsAggregatedByDay.
-func (mr *MockVisitRepositoryMockRecorder) AnalyticsAggregatedByDay(ctx, shortURL any) *gomock.Call {
+func (mr *MockVisitRepositoryMockRecorder) AnalyticsAggregatedByDay(ctx any, linkIDs ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyticsAggregatedByDay", reflect.TypeOf((*MockVisitRepository)(nil).AnalyticsAggregatedByDay), ctx, shortURL)
+	varargs := append([]any{ctx}, linkIDs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyticsAggregatedByDay", reflect.TypeOf((*MockVisitRepository)(nil).AnalyticsAggregatedByDay), varargs...)
 }
 
 // AnalyticsAggregatedByMonth mocks base method.
-func (m *MockVisitRepository) AnalyticsAggregatedByMonth(ctx context.Context, shortURL string) ([]visit.PeriodCount, error) {
+func (m *MockVisitRepository) AnalyticsAggregatedByMonth(ctx context.Context, linkIDs ...uuid.UUID) ([]visit.PeriodCount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AnalyticsAggregatedByMonth", ctx, shortURL)
+	varargs := []any{ctx}
+	for _, a := range linkIDs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AnalyticsAggregatedByMonth", varargs...)
 	ret0, _ := ret[0].([]visit.PeriodCount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AnalyticsAggregatedByMonth indicates an expected call of AnalyticsAggregatedByMonth.
-func (mr *MockVisitRepositoryMockRecorder) AnalyticsAggregatedByMonth(ctx, shortURL any) *gomock.Call {
+func (mr *MockVisitRepositoryMockRecorder) AnalyticsAggregatedByMonth(ctx any, linkIDs ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyticsAggregatedByMonth", reflect.TypeOf((*MockVisitRepository)(nil).AnalyticsAggregatedByMonth), ctx, shortURL)
+	varargs := append([]any{ctx}, linkIDs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyticsAggregatedByMonth", reflect.TypeOf((*MockVisitRepository)(nil).AnalyticsAggregatedByMonth), varargs...)
+}
+
+// AnalyticsAggregatedBySource mocks base method.
+func (m *MockVisitRepository) AnalyticsAggregatedBySource(ctx context.Context, linkIDs ...uuid.UUID) ([]visit.SourceCount, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range linkIDs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AnalyticsAggregatedBySource", varargs...)
+	ret0, _ := ret[0].([]visit.SourceCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnalyticsAggregatedBySource indicates an expected call of AnalyticsAggregatedBySource.
+func (mr *MockVisitRepositoryMockRecorder) AnalyticsAggregatedBySource(ctx any, linkIDs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, linkIDs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyticsAggregatedBySource", reflect.TypeOf((*MockVisitRepository)(nil).AnalyticsAggregatedBySource), varargs...)
 }
 
 // AnalyticsAggregatedByUserAgent mocks base method.
-func (m *MockVisitRepository) AnalyticsAggregatedByUserAgent(ctx context.Context, shortURL string) ([]visit.UserAgentCount, error) {
+func (m *MockVisitRepository) AnalyticsAggregatedByUserAgent(ctx context.Context, linkIDs ...uuid.UUID) ([]visit.UserAgentCount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AnalyticsAggregatedByUserAgent", ctx, shortURL)
+	varargs := []any{ctx}
+	for _, a := range linkIDs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AnalyticsAggregatedByUserAgent", varargs...)
 	ret0, _ := ret[0].([]visit.UserAgentCount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AnalyticsAggregatedByUserAgent indicates an expected call of AnalyticsAggregatedByUserAgent.
-func (mr *MockVisitRepositoryMockRecorder) AnalyticsAggregatedByUserAgent(ctx, shortURL any) *gomock.Call {
+func (mr *MockVisitRepositoryMockRecorder) AnalyticsAggregatedByUserAgent(ctx any, linkIDs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, linkIDs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyticsAggregatedByUserAgent", reflect.TypeOf((*MockVisitRepository)(nil).AnalyticsAggregatedByUserAgent), varargs...)
+}
+
+// AnalyticsAggregatedByVariant mocks base method.
+func (m *MockVisitRepository) AnalyticsAggregatedByVariant(ctx context.Context, linkID uuid.UUID) ([]visit.VariantCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnalyticsAggregatedByVariant", ctx, linkID)
+	ret0, _ := ret[0].([]visit.VariantCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnalyticsAggregatedByVariant indicates an expected call of AnalyticsAggregatedByVariant.
+func (mr *MockVisitRepositoryMockRecorder) AnalyticsAggregatedByVariant(ctx, linkID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyticsAggregatedByUserAgent", reflect.TypeOf((*MockVisitRepository)(nil).AnalyticsAggregatedByUserAgent), ctx, shortURL)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyticsAggregatedByVariant", reflect.TypeOf((*MockVisitRepository)(nil).AnalyticsAggregatedByVariant), ctx, linkID)
 }
 
 // CreateBatch mocks base method.
@@ -97,3 +148,18 @@ func (mr *MockVisitRepositoryMockRecorder) CreateBatch(ctx, visits any) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockVisitRepository)(nil).CreateBatch), ctx, visits)
 }
+
+// TotalVisits mocks base method.
+func (m *MockVisitRepository) TotalVisits(ctx context.Context, linkID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TotalVisits", ctx, linkID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TotalVisits indicates an expected call of TotalVisits.
+func (mr *MockVisitRepositoryMockRecorder) TotalVisits(ctx, linkID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotalVisits", reflect.TypeOf((*MockVisitRepository)(nil).TotalVisits), ctx, linkID)
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"shortener/src/internal/application/contracts"
+	shortlink "shortener/src/internal/domain/short_link"
+	"shortener/src/pkg/logger"
+	"time"
+)
+
+const qrCodeKeyPrefix = "qr:"
+
+type QRCodeService struct {
+	encoder shortlink.QRCodeEncoder
+	cache   contracts.Cache
+	ttl     time.Duration
+}
+
+func NewQRCodeService(encoder shortlink.QRCodeEncoder, cache contracts.Cache, ttl time.Duration) *QRCodeService {
+	return &QRCodeService{
+		encoder: encoder,
+		cache:   cache,
+		ttl:     ttl,
+	}
+}
+
+// Render returns the cached image for the content and options or renders and caches it. A cache outage only
+// costs a render.
+func (s *QRCodeService) Render(ctx context.Context, content string, options shortlink.QRCodeOptions) ([]byte, error) {
+	key := qrCodeKey(content, options)
+	if value, err := s.cache.Get(ctx, key); err == nil {
+		return []byte(value), nil
+	}
+
+	image, err := s.encoder.Encode(content, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Set(ctx, key, image, s.ttl); err != nil {
+		logger.Error("failed to set qr code into cache", "err", err)
+	}
+
+	return image, nil
+}
+
+func qrCodeKey(content string, options shortlink.QRCodeOptions) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%+v", content, options))
+	return qrCodeKeyPrefix + hex.EncodeToString(sum[:])
+}
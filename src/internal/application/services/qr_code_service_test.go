@@ -0,0 +1,65 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"shortener/src/internal/application/services"
+	"shortener/src/internal/application/services/mocks"
+	shortlink "shortener/src/internal/domain/short_link"
+
+	"go.uber.org/mock/gomock"
+)
+
+type countingEncoder struct {
+	calls int
+}
+
+func (e *countingEncoder) Encode(content string, _ shortlink.QRCodeOptions) ([]byte, error) {
+	e.calls++
+	return []byte("image of " + content), nil
+}
+
+func TestQRCodeService_Render_CacheHit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Any()).Return("cached", nil)
+
+	encoder := &countingEncoder{}
+	svc := services.NewQRCodeService(encoder, mockCache, 0)
+
+	image, err := svc.Render(context.Background(), "https://sho.rt/s/abc", shortlink.QRCodeOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(image) != "cached" || encoder.calls != 0 {
+		t.Fatalf("expected the cached image without rendering, got %q after %d renders", image, encoder.calls)
+	}
+}
+
+func TestQRCodeService_Render_CacheMiss_RendersAndCaches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Any()).Return("", errors.New("miss"))
+
+	mockCache.EXPECT().
+		Set(gomock.Any(), gomock.Any(), gomock.Eq([]byte("image of https://sho.rt/s/abc")), gomock.Any()).
+		Return(nil)
+
+	encoder := &countingEncoder{}
+	svc := services.NewQRCodeService(encoder, mockCache, 0)
+
+	if _, err := svc.Render(context.Background(), "https://sho.rt/s/abc", shortlink.QRCodeOptions{Size: 256}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if encoder.calls != 1 {
+		t.Fatalf("expected one render, got %d", encoder.calls)
+	}
+}
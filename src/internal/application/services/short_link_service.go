@@ -2,60 +2,112 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"shortener/src/internal/application/contracts"
 	shortlink "shortener/src/internal/domain/short_link"
 	"shortener/src/pkg/logger"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type ShortLinkService struct {
 	shortLinkRepository shortlink.ShortLinkRepository
 	generator           shortlink.ShortLinkGenerator
+	codePolicy          shortlink.CodePolicy
+	aliasMinLength      int
+	aliasMaxLength      int
+	urlPolicy           shortlink.URLPolicy
 	cache               contracts.Cache
+	stripTracking       bool
 }
 
 const maxCreateAttempts = 5
+const maxAliasSuggestions = 5
 const cachedURLTTL = time.Hour * 5
+const clicksKeyPrefix = "clicks:"
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 1000
+)
 
 func NewShortLinkService(
 	shortLinkRepo shortlink.ShortLinkRepository,
 	generator shortlink.ShortLinkGenerator,
+	codePolicy shortlink.CodePolicy,
+	aliasMinLength, aliasMaxLength int,
+	urlPolicy shortlink.URLPolicy,
 	cache contracts.Cache,
+	stripTrackingParams bool,
 ) *ShortLinkService {
 	return &ShortLinkService{
 		shortLinkRepository: shortLinkRepo,
 		generator:           generator,
+		codePolicy:          codePolicy,
+		aliasMinLength:      aliasMinLength,
+		aliasMaxLength:      aliasMaxLength,
+		urlPolicy:           urlPolicy,
 		cache:               cache,
+		stripTracking:       stripTrackingParams,
 	}
 }
 
 func (s *ShortLinkService) Create(
 	ctx context.Context,
-	shortURL, originalURL string,
+	params shortlink.CreateParams,
 ) (*shortlink.ShortLink, error) {
-	customURL := shortURL != ""
+	requested := params
+	params, err := s.prepareCreateParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	customURL := params.ShortCode != ""
+	if customURL {
+		if err := s.codePolicy.Check(params.ShortCode); err != nil {
+			return nil, err
+		}
+	}
+
 	for attempt := 0; attempt < maxCreateAttempts; attempt++ {
-		if shortURL == "" {
-			generated, err := s.generator.Generate()
+		if params.ShortCode == "" {
+			generated, err := s.generateCode(ctx, params.OriginalURL, attempt)
 			if err != nil {
 				return nil, err
 			}
-			shortURL = generated
+			if generated == "" {
+				continue
+			}
+			params.ShortCode = generated
 		}
 
-		link, err := s.shortLinkRepository.Create(ctx, shortURL, originalURL)
+		link, err := s.shortLinkRepository.Create(ctx, params)
 		if err == nil {
 			return link, nil
 		}
 
-		if errors.Is(err, shortlink.ErrShortLinkAlreadyExists) && customURL {
+		if !errors.Is(err, shortlink.ErrShortLinkAlreadyExists) || customURL {
 			return nil, err
 		}
 
-		shortURL = ""
+		existing, err := s.sameTargetLink(ctx, params, requested)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, nil
+		}
+
+		params.ShortCode = ""
 	}
 
 	return nil, fmt.Errorf(
@@ -64,24 +116,279 @@ func (s *ShortLinkService) Create(
 	)
 }
 
-func (s *ShortLinkService) Get(ctx context.Context, shortURL string) (*shortlink.ShortLink, error) {
-	value, err := s.cache.Get(ctx, shortURL)
-	if err != nil {
-		dbValue, err := s.shortLinkRepository.Get(ctx, shortURL)
+func (s *ShortLinkService) CreateBatch(
+	ctx context.Context,
+	params []shortlink.CreateParams,
+) ([]shortlink.CreateResult, error) {
+	results := make([]shortlink.CreateResult, len(params))
+	custom := make([]bool, len(params))
+	pending := make([]int, 0, len(params))
+	requested := slices.Clone(params)
+
+	for i := range params {
+		prepared, err := s.prepareCreateParams(ctx, params[i])
 		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		if prepared.ShortCode != "" {
+			if err := s.codePolicy.Check(prepared.ShortCode); err != nil {
+				results[i].Err = err
+				continue
+			}
+		}
+
+		params[i] = prepared
+		custom[i] = prepared.ShortCode != ""
+		pending = append(pending, i)
+	}
+
+	for attempt := 0; attempt < maxCreateAttempts && len(pending) > 0; attempt++ {
+		batch := make([]shortlink.CreateParams, 0, len(pending))
+		sent := make([]int, 0, len(pending))
+		retry := make([]int, 0)
+		for _, i := range pending {
+			if !custom[i] {
+				generated, err := s.generateCode(ctx, params[i].OriginalURL, attempt)
+				if err != nil {
+					return nil, err
+				}
+				if generated == "" {
+					retry = append(retry, i)
+					continue
+				}
+				params[i].ShortCode = generated
+			}
+			batch = append(batch, params[i])
+			sent = append(sent, i)
+		}
+
+		var created []shortlink.CreateResult
+		if len(batch) > 0 {
+			var err error
+			created, err = s.shortLinkRepository.CreateBatch(ctx, batch)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for j, i := range sent {
+			results[i] = created[j]
+			if !errors.Is(created[j].Err, shortlink.ErrShortLinkAlreadyExists) || custom[i] {
+				continue
+			}
+
+			existing, err := s.sameTargetLink(ctx, params[i], requested[i])
+			if err != nil {
+				return nil, err
+			}
+
+			if existing != nil {
+				results[i] = shortlink.CreateResult{Link: existing}
+				continue
+			}
+			retry = append(retry, i)
+		}
+		pending = retry
+	}
+
+	for _, i := range pending {
+		results[i].Err = fmt.Errorf(
+			"failed to create short link after %d attempts: %w",
+			maxCreateAttempts, shortlink.ErrShortLinkAlreadyExists,
+		)
+	}
+
+	return results, nil
+}
+
+// generateCode returns "" when the code policy rejects the generated code, which callers treat like a
+// collision. The random generator checks its codes itself; sequential and hash codes are checked only here.
+func (s *ShortLinkService) generateCode(ctx context.Context, originalURL string, attempt int) (string, error) {
+	code, err := s.generator.Generate(ctx, originalURL, attempt)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.codePolicy.Check(code); err != nil {
+		if errors.Is(err, shortlink.ErrShortCodeNotAllowed) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return code, nil
+}
+
+// sameTargetLink returns the live link that took the generated params.ShortCode when it is the owner's link
+// to the same target with the requested options. The hash generator gives a URL the same codes every time,
+// so shortening it again collides with the earlier link, which is then the answer rather than a conflict.
+func (s *ShortLinkService) sameTargetLink(
+	ctx context.Context,
+	params, requested shortlink.CreateParams,
+) (*shortlink.ShortLink, error) {
+	if params.OwnerID == nil {
+		return nil, nil
+	}
+
+	link, err := s.shortLinkRepository.Get(ctx, params.Domain, params.ShortCode)
+	if err != nil {
+		if errors.Is(err, shortlink.ErrShortLinkNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if link.OriginalURL != params.OriginalURL || !link.OwnedBy(params.OwnerID) || link.Deleted() ||
+		link.Expired(time.Now()) || link.Exhausted() {
+		return nil, nil
+	}
+
+	same, err := sameLinkOptions(link, requested)
+	if err != nil || !same {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (s *ShortLinkService) SuggestAliases(ctx context.Context, domain, alias string) ([]string, error) {
+	candidates := make([]string, 0)
+	for _, code := range aliasCandidates(alias, s.aliasMinLength, s.aliasMaxLength) {
+		if s.codePolicy.Check(code) == nil {
+			candidates = append(candidates, code)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	available, err := s.shortLinkRepository.AvailableCodes(ctx, domain, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(available) > maxAliasSuggestions {
+		available = available[:maxAliasSuggestions]
+	}
+
+	return available, nil
+}
+
+// GetOrCreate returns the owner's live deduplicated link for the target or creates one. An expired or exhausted
+// link is retired so a new one takes its place; a live link with other options is ErrDedupeOptionsMismatch.
+func (s *ShortLinkService) GetOrCreate(
+	ctx context.Context,
+	params shortlink.CreateParams,
+) (*shortlink.ShortLink, bool, error) {
+	if params.OwnerID == nil {
+		return nil, false, shortlink.ErrDedupeRequiresOwner
+	}
+
+	hash := targetHash(s.canonicalURL(params.OriginalURL))
+
+	link, err := s.reusableLink(ctx, params, hash)
+	if err == nil {
+		return link, false, nil
+	}
+
+	if !errors.Is(err, shortlink.ErrShortLinkNotFound) {
+		return nil, false, err
+	}
+
+	params.Dedupe = true
+	link, err = s.Create(ctx, params)
+	if err == nil {
+		return link, true, nil
+	}
+
+	if !errors.Is(err, shortlink.ErrTargetAlreadyShortened) {
+		return nil, false, err
+	}
+
+	link, err = s.reusableLink(ctx, params, hash)
+	if err != nil {
+		if errors.Is(err, shortlink.ErrShortLinkNotFound) {
+			return nil, false, shortlink.ErrTargetAlreadyShortened
+		}
+
+		return nil, false, err
+	}
+
+	return link, false, nil
+}
+
+// reusableLink returns the deduplicated link GetOrCreate may hand back for params.
+func (s *ShortLinkService) reusableLink(
+	ctx context.Context,
+	params shortlink.CreateParams,
+	hash string,
+) (*shortlink.ShortLink, error) {
+	link, err := s.shortLinkRepository.GetDeduplicated(ctx, params.OwnerID, params.Domain, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if link.Expired(time.Now()) || link.Exhausted() {
+		if err := s.shortLinkRepository.RetireDeduplicated(ctx, link.ID); err != nil {
 			return nil, err
 		}
 
-		bytes, err := json.Marshal(dbValue)
+		return nil, shortlink.ErrShortLinkNotFound
+	}
+
+	same, err := sameLinkOptions(link, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if !same {
+		return nil, shortlink.ErrDedupeOptionsMismatch
+	}
+
+	return link, nil
+}
+
+// sameLinkOptions reports whether the link was created with the expiry, click limit, fallback and password
+// the params ask for.
+func sameLinkOptions(link *shortlink.ShortLink, params shortlink.CreateParams) (bool, error) {
+	if link.FallbackURL != params.FallbackURL {
+		return false, nil
+	}
+
+	if (link.ExpiresAt == nil) != (params.ExpiresAt == nil) ||
+		link.ExpiresAt != nil && !link.ExpiresAt.Equal(*params.ExpiresAt) {
+		return false, nil
+	}
+
+	if (link.MaxClicks == nil) != (params.MaxClicks == nil) ||
+		link.MaxClicks != nil && *link.MaxClicks != *params.MaxClicks {
+		return false, nil
+	}
+
+	if params.Password == "" || !link.Protected() {
+		return params.Password == "" && !link.Protected(), nil
+	}
+
+	return passwordMatches(link.PasswordHash, params.Password)
+}
+
+func (s *ShortLinkService) Get(ctx context.Context, domain, shortURL string) (*shortlink.ShortLink, error) {
+	key := cacheKey(domain, shortURL)
+	value, err := s.cache.Get(ctx, key)
+	if err != nil {
+		dbValue, err := s.shortLinkRepository.Get(ctx, domain, shortURL)
 		if err != nil {
-			logger.Error("failed to marshal short link", "err", err)
-		} else {
-			if err := s.cache.Set(ctx, shortURL, bytes, cachedURLTTL); err != nil {
-				logger.Error("failed to set URL into cache", "err", err)
-			}
+			return nil, err
 		}
 
-		return dbValue, nil
+		s.setCache(ctx, key, dbValue)
+
+		return checkAvailability(dbValue)
 	}
 
 	var model shortlink.ShortLink
@@ -89,5 +396,460 @@ func (s *ShortLinkService) Get(ctx context.Context, shortURL string) (*shortlink
 		return nil, err
 	}
 
-	return &model, nil
+	return checkAvailability(&model)
+}
+
+func (s *ShortLinkService) ConsumeClick(ctx context.Context, link *shortlink.ShortLink) error {
+	if link.MaxClicks == nil {
+		return nil
+	}
+
+	if link.Exhausted() {
+		return shortlink.ErrShortLinkExhausted
+	}
+
+	key := clicksKeyPrefix + link.ID.String()
+	count, err := s.cache.Increment(ctx, key, cachedURLTTL)
+	if err != nil {
+		logger.Error("failed to increment clicks counter", "err", err)
+	} else if count > *link.MaxClicks {
+		return shortlink.ErrShortLinkExhausted
+	}
+
+	err = s.shortLinkRepository.ConsumeClick(ctx, link.ID)
+	// Postgres decides: a click it did not record gives its slot in the counter back.
+	if err != nil && !errors.Is(err, shortlink.ErrShortLinkExhausted) && count > 0 {
+		if err := s.cache.Decrement(ctx, key); err != nil {
+			logger.Error("failed to decrement clicks counter", "err", err)
+		}
+	}
+
+	return err
+}
+
+func (s *ShortLinkService) Owned(
+	ctx context.Context,
+	ownerID *uuid.UUID,
+	domain, shortURL string,
+) (*shortlink.ShortLink, error) {
+	link, err := s.shortLinkRepository.Get(ctx, domain, shortURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if !link.ReadableBy(ownerID) {
+		return nil, shortlink.ErrForbidden
+	}
+
+	return link, nil
+}
+
+func (s *ShortLinkService) List(ctx context.Context, params shortlink.ListParams) (*shortlink.ListPage, error) {
+	if params.Limit <= 0 {
+		params.Limit = defaultListLimit
+	}
+	params.Limit = min(params.Limit, maxListLimit)
+
+	if params.Sort == "" {
+		params.Sort = shortlink.ListSortCreatedAt
+	}
+
+	if params.Filter.TargetDomain != "" {
+		params.Filter.TargetDomain = shortlink.CanonicalHost(params.Filter.TargetDomain)
+	}
+
+	return s.shortLinkRepository.List(ctx, params)
+}
+
+func (s *ShortLinkService) Update(
+	ctx context.Context,
+	ownerID *uuid.UUID,
+	domain, shortURL string,
+	params shortlink.UpdateParams,
+) (*shortlink.ShortLink, error) {
+	link, err := s.getModifiable(ctx, ownerID, domain, shortURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.OriginalURL != nil {
+		raw := *params.OriginalURL
+		canonical := s.canonicalURL(raw)
+		if err := s.urlPolicy.Check(ctx, canonical); err != nil {
+			return nil, err
+		}
+
+		hash := targetHash(canonical)
+		hostname := targetDomain(canonical)
+		params.OriginalURL = &canonical
+		params.RawURL = &raw
+		params.TargetHash = &hash
+		params.TargetDomain = &hostname
+	}
+
+	if params.Rules != nil {
+		rules, err := s.prepareRules(ctx, *params.Rules)
+		if err != nil {
+			return nil, err
+		}
+		params.Rules = &rules
+	}
+
+	if params.Variants != nil {
+		variants, err := s.prepareVariants(ctx, *params.Variants)
+		if err != nil {
+			return nil, err
+		}
+		params.Variants = &variants
+	}
+
+	if params.DeepLink != nil && !params.DeepLink.Empty() {
+		if err := s.prepareDeepLink(ctx, params.DeepLink); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.shortLinkRepository.Update(ctx, link.ID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	s.evictCache(ctx, cacheKey(domain, shortURL))
+
+	return updated, nil
+}
+
+func (s *ShortLinkService) Delete(ctx context.Context, ownerID *uuid.UUID, domain, shortURL string) error {
+	link, err := s.getModifiable(ctx, ownerID, domain, shortURL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.shortLinkRepository.Delete(ctx, link.ID); err != nil {
+		return err
+	}
+
+	s.evictCache(ctx, cacheKey(domain, shortURL))
+
+	return nil
+}
+
+func (s *ShortLinkService) AttachTags(
+	ctx context.Context,
+	ownerID *uuid.UUID,
+	domain, shortURL string,
+	tags []string,
+) (*shortlink.ShortLink, error) {
+	tags, err := shortlink.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.getModifiable(ctx, ownerID, domain, shortURL)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := slices.Clone(link.Tags)
+	for _, tag := range tags {
+		if !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+
+	if len(merged) > shortlink.MaxTags {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", shortlink.ErrInvalidTag, shortlink.MaxTags)
+	}
+
+	if err := s.shortLinkRepository.AttachTags(ctx, link.ID, *link.OwnerID, tags); err != nil {
+		return nil, err
+	}
+
+	s.evictCache(ctx, cacheKey(domain, shortURL))
+
+	slices.Sort(merged)
+	link.Tags = merged
+
+	return link, nil
+}
+
+func (s *ShortLinkService) DetachTag(ctx context.Context, ownerID *uuid.UUID, domain, shortURL, tag string) error {
+	tag, err := shortlink.NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.getModifiable(ctx, ownerID, domain, shortURL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.shortLinkRepository.DetachTag(ctx, link.ID, *link.OwnerID, tag); err != nil {
+		return err
+	}
+
+	s.evictCache(ctx, cacheKey(domain, shortURL))
+
+	return nil
+}
+
+func (s *ShortLinkService) LinkIDsByTag(ctx context.Context, ownerID *uuid.UUID, tag string) ([]uuid.UUID, error) {
+	tag, err := shortlink.NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.shortLinkRepository.IDsByTag(ctx, ownerID, tag)
+}
+
+func (s *ShortLinkService) getModifiable(
+	ctx context.Context,
+	ownerID *uuid.UUID,
+	domain, shortURL string,
+) (*shortlink.ShortLink, error) {
+	link, err := s.shortLinkRepository.Get(ctx, domain, shortURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if !link.OwnedBy(ownerID) {
+		return nil, shortlink.ErrForbidden
+	}
+
+	if link.Deleted() {
+		return nil, shortlink.ErrShortLinkDeleted
+	}
+
+	return link, nil
+}
+
+func (s *ShortLinkService) evictCache(ctx context.Context, key string) {
+	if err := s.cache.Delete(ctx, key); err != nil {
+		logger.Error("failed to evict URL from cache", "err", err)
+	}
+}
+
+func (s *ShortLinkService) setCache(ctx context.Context, key string, link *shortlink.ShortLink) {
+	ttl := cachedURLTTL
+	if link.ExpiresAt != nil {
+		ttl = min(ttl, time.Until(*link.ExpiresAt))
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	bytes, err := json.Marshal(link)
+	if err != nil {
+		logger.Error("failed to marshal short link", "err", err)
+		return
+	}
+
+	if err := s.cache.Set(ctx, key, bytes, ttl); err != nil {
+		logger.Error("failed to set URL into cache", "err", err)
+	}
+}
+
+func cacheKey(domain, shortURL string) string {
+	return domain + "/" + shortURL
+}
+
+func checkAvailability(link *shortlink.ShortLink) (*shortlink.ShortLink, error) {
+	if link.Deleted() {
+		return link, shortlink.ErrShortLinkDeleted
+	}
+
+	if link.Expired(time.Now()) {
+		return link, shortlink.ErrShortLinkExpired
+	}
+
+	return link, nil
+}
+
+func (s *ShortLinkService) prepareCreateParams(
+	ctx context.Context,
+	params shortlink.CreateParams,
+) (shortlink.CreateParams, error) {
+	params.RawURL = params.OriginalURL
+	params.OriginalURL = s.canonicalURL(params.OriginalURL)
+
+	if params.RedirectCode == 0 {
+		params.RedirectCode = shortlink.DefaultRedirectCode
+	}
+
+	if params.QueryPassthrough == "" {
+		params.QueryPassthrough = shortlink.QueryPassthroughOff
+	}
+
+	if err := s.urlPolicy.Check(ctx, params.OriginalURL); err != nil {
+		return params, err
+	}
+
+	if params.FallbackURL != "" {
+		if err := s.urlPolicy.Check(ctx, params.FallbackURL); err != nil {
+			return params, fmt.Errorf("fallback URL: %w", err)
+		}
+	}
+
+	rules, err := s.prepareRules(ctx, params.Rules)
+	if err != nil {
+		return params, err
+	}
+	params.Rules = rules
+
+	variants, err := s.prepareVariants(ctx, params.Variants)
+	if err != nil {
+		return params, err
+	}
+	params.Variants = variants
+
+	if params.DeepLink != nil && params.DeepLink.Empty() {
+		params.DeepLink = nil
+	}
+
+	if params.DeepLink != nil {
+		if err := s.prepareDeepLink(ctx, params.DeepLink); err != nil {
+			return params, err
+		}
+	}
+
+	if params.Password != "" {
+		hash, err := hashPassword(params.Password)
+		if err != nil {
+			return params, err
+		}
+		params.PasswordHash = hash
+		params.Password = ""
+	}
+
+	params.TargetHash = targetHash(params.OriginalURL)
+	params.TargetDomain = targetDomain(params.OriginalURL)
+
+	return params, nil
+}
+
+// prepareRules validates the rules and stores their targets canonical, like OriginalURL, after checking
+// them against the URL policy.
+func (s *ShortLinkService) prepareRules(ctx context.Context, rules []shortlink.Rule) ([]shortlink.Rule, error) {
+	rules, err := shortlink.NormalizeRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rules {
+		rules[i].TargetURL = s.canonicalURL(rules[i].TargetURL)
+		if err := s.urlPolicy.Check(ctx, rules[i].TargetURL); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rules[i].Name, err)
+		}
+	}
+
+	return rules, nil
+}
+
+// prepareVariants validates the split variants and treats their targets like OriginalURL.
+func (s *ShortLinkService) prepareVariants(
+	ctx context.Context,
+	variants []shortlink.Variant,
+) ([]shortlink.Variant, error) {
+	variants, err := shortlink.NormalizeVariants(variants)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range variants {
+		variants[i].TargetURL = s.canonicalURL(variants[i].TargetURL)
+		if err := s.urlPolicy.Check(ctx, variants[i].TargetURL); err != nil {
+			return nil, fmt.Errorf("variant %q: %w", variants[i].Name, err)
+		}
+	}
+
+	return variants, nil
+}
+
+// prepareDeepLink validates the deep link and checks its web URLs, including universal links, against the
+// URL policy. Custom scheme URIs only open apps and are not checked.
+func (s *ShortLinkService) prepareDeepLink(ctx context.Context, deepLink *shortlink.DeepLink) error {
+	if err := deepLink.Validate(); err != nil {
+		return err
+	}
+
+	if deepLink.WebURL != "" {
+		deepLink.WebURL = s.canonicalURL(deepLink.WebURL)
+		if err := s.urlPolicy.Check(ctx, deepLink.WebURL); err != nil {
+			return fmt.Errorf("deep link web URL: %w", err)
+		}
+	}
+
+	for _, appURL := range []string{deepLink.IOSURL, deepLink.AndroidURL} {
+		lower := strings.ToLower(appURL)
+		if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+			continue
+		}
+
+		if err := s.urlPolicy.Check(ctx, appURL); err != nil {
+			return fmt.Errorf("deep link app URL: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// aliasCandidates derives alternatives to a taken alias: the other plural form, other separators,
+// an incremented trailing number and numeric suffixes, in that order, keeping only those within
+// minLength..maxLength that pass shortlink.ValidAlias.
+func aliasCandidates(alias string, minLength, maxLength int) []string {
+	var candidates []string
+	seen := map[string]bool{alias: true}
+	add := func(code string) {
+		if seen[code] || len(code) < minLength || len(code) > maxLength || !shortlink.ValidAlias(code) {
+			return
+		}
+		seen[code] = true
+		candidates = append(candidates, code)
+	}
+
+	if base, ok := strings.CutSuffix(alias, "s"); ok {
+		add(base)
+	} else {
+		add(alias + "s")
+	}
+
+	add(strings.ReplaceAll(alias, "-", "_"))
+	add(strings.ReplaceAll(alias, "_", "-"))
+	add(strings.NewReplacer("-", "", "_", "").Replace(alias))
+
+	prefix := strings.TrimRight(alias, "0123456789")
+	if n, err := strconv.Atoi(alias[len(prefix):]); err == nil {
+		for i := 1; i <= 3; i++ {
+			add(prefix + strconv.Itoa(n+i))
+		}
+	}
+
+	for i := 1; i <= 5; i++ {
+		add(alias + "-" + strconv.Itoa(i))
+	}
+	for i := 1; i <= 5; i++ {
+		add(alias + strconv.Itoa(i))
+	}
+
+	return candidates
+}
+
+func (s *ShortLinkService) canonicalURL(rawURL string) string {
+	return shortlink.CanonicalURL(rawURL, s.stripTracking)
+}
+
+// targetHash identifies a canonical target URL for deduplication.
+func targetHash(canonicalURL string) string {
+	sum := sha256.Sum256([]byte(canonicalURL))
+	return hex.EncodeToString(sum[:])
+}
+
+func targetDomain(originalURL string) string {
+	u, err := url.Parse(originalURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(u.Hostname())
 }
@@ -4,22 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"shortener/src/internal/application/services/mocks"
+	"slices"
 	"testing"
+	"time"
 
 	"shortener/src/internal/application/services"
 	shortlink "shortener/src/internal/domain/short_link"
 
+	"github.com/google/uuid"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type seqGenerator struct {
-	vals []string
-	i    int
-	err  error
+	vals     []string
+	i        int
+	err      error
+	attempts []int
 }
 
-func (g *seqGenerator) Generate() (string, error) {
+func (g *seqGenerator) Generate(_ context.Context, _ string, attempt int) (string, error) {
+	g.attempts = append(g.attempts, attempt)
 	if g.err != nil {
 		return "", g.err
 	}
@@ -31,6 +38,59 @@ func (g *seqGenerator) Generate() (string, error) {
 	return v, nil
 }
 
+type allowAllPolicy struct{}
+
+func (allowAllPolicy) Check(string) error {
+	return nil
+}
+
+type allowAllURLs struct{}
+
+func (allowAllURLs) Check(context.Context, string) error {
+	return nil
+}
+
+type rejectURLs struct{}
+
+func (rejectURLs) Check(context.Context, string) error {
+	return shortlink.ErrTargetNotAllowed
+}
+
+type blockedPolicy map[string]bool
+
+func (p blockedPolicy) Check(code string) error {
+	if p[code] {
+		return shortlink.ErrShortCodeNotAllowed
+	}
+	return nil
+}
+
+func newShortLinkService(
+	repo shortlink.ShortLinkRepository,
+	gen shortlink.ShortLinkGenerator,
+	cache *mocks.MockCache,
+) *services.ShortLinkService {
+	return services.NewShortLinkService(repo, gen, allowAllPolicy{}, 1, 32, allowAllURLs{}, cache, false)
+}
+
+type createParamsMatcher struct {
+	shortCode   string
+	originalURL string
+}
+
+func createParams(shortCode, originalURL string) gomock.Matcher {
+	return createParamsMatcher{shortCode: shortCode, originalURL: originalURL}
+}
+
+func (m createParamsMatcher) Matches(x any) bool {
+	params, ok := x.(shortlink.CreateParams)
+	return ok && params.ShortCode == m.shortCode && params.OriginalURL == m.originalURL
+}
+
+func (m createParamsMatcher) String() string {
+	return fmt.Sprintf("create params with code %q and url %q", m.shortCode, m.originalURL)
+}
+
 func TestShortLinkService_Create_SuccessGenerated(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -41,13 +101,13 @@ func TestShortLinkService_Create_SuccessGenerated(t *testing.T) {
 	gen := &seqGenerator{vals: []string{"gen1"}}
 
 	mockRepo.EXPECT().
-		Create(gomock.Any(), gomock.Eq("gen1"), gomock.Eq("https://example.com")).
+		Create(gomock.Any(), createParams("gen1", "https://example.com/")).
 		Return(&shortlink.ShortLink{}, nil)
 
-	svc := services.NewShortLinkService(mockRepo, gen, mockCache)
+	svc := newShortLinkService(mockRepo, gen, mockCache)
 
 	ctx := context.Background()
-	link, err := svc.Create(ctx, "", "https://example.com")
+	link, err := svc.Create(ctx, shortlink.CreateParams{OriginalURL: "https://example.com"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -66,11 +126,11 @@ func TestShortLinkService_Create_CustomConflict_ReturnsError(t *testing.T) {
 	gen := &seqGenerator{vals: []string{"unused"}}
 
 	mockRepo.EXPECT().
-		Create(gomock.Any(), gomock.Eq("custom"), gomock.Eq("https://ex")).
+		Create(gomock.Any(), createParams("custom", "https://ex/")).
 		Return(nil, shortlink.ErrShortLinkAlreadyExists)
 
-	svc := services.NewShortLinkService(mockRepo, gen, mockCache)
-	_, err := svc.Create(context.Background(), "custom", "https://ex")
+	svc := newShortLinkService(mockRepo, gen, mockCache)
+	_, err := svc.Create(context.Background(), shortlink.CreateParams{ShortCode: "custom", OriginalURL: "https://ex"})
 	if !errors.Is(err, shortlink.ErrShortLinkAlreadyExists) {
 		t.Fatalf("expected ErrShortLinkAlreadyExists, got: %v", err)
 	}
@@ -86,21 +146,24 @@ func TestShortLinkService_Create_RetryThenSuccess(t *testing.T) {
 	gen := &seqGenerator{vals: []string{"a", "b"}}
 
 	first := mockRepo.EXPECT().
-		Create(gomock.Any(), gomock.Eq("a"), gomock.Eq("o")).
+		Create(gomock.Any(), createParams("a", "o")).
 		Return(nil, shortlink.ErrShortLinkAlreadyExists)
 	second := mockRepo.EXPECT().
-		Create(gomock.Any(), gomock.Eq("b"), gomock.Eq("o")).
+		Create(gomock.Any(), createParams("b", "o")).
 		Return(&shortlink.ShortLink{}, nil)
 	gomock.InOrder(first, second)
 
-	svc := services.NewShortLinkService(mockRepo, gen, mockCache)
-	link, err := svc.Create(context.Background(), "", "o")
+	svc := newShortLinkService(mockRepo, gen, mockCache)
+	link, err := svc.Create(context.Background(), shortlink.CreateParams{OriginalURL: "o"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if link == nil {
 		t.Fatalf("expected non-nil link")
 	}
+	if len(gen.attempts) != 2 || gen.attempts[0] != 0 || gen.attempts[1] != 1 {
+		t.Fatalf("expected attempts [0 1], got %v", gen.attempts)
+	}
 }
 
 func TestShortLinkService_Create_FailedAfterAttempts(t *testing.T) {
@@ -112,17 +175,72 @@ func TestShortLinkService_Create_FailedAfterAttempts(t *testing.T) {
 
 	gen := &seqGenerator{vals: []string{"x"}}
 	mockRepo.EXPECT().
-		Create(gomock.Any(), gomock.Any(), gomock.Any()).
+		Create(gomock.Any(), gomock.Any()).
 		Return(nil, shortlink.ErrShortLinkAlreadyExists).
 		AnyTimes()
 
-	svc := services.NewShortLinkService(mockRepo, gen, mockCache)
-	_, err := svc.Create(context.Background(), "", "orig")
+	svc := newShortLinkService(mockRepo, gen, mockCache)
+	_, err := svc.Create(context.Background(), shortlink.CreateParams{OriginalURL: "orig"})
 	if err == nil {
 		t.Fatalf("expected error after attempts, got nil")
 	}
 }
 
+func TestShortLinkService_Create_CollisionWithOwnSameTarget_ReturnsExisting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	owner := uuid.New()
+	existing := &shortlink.ShortLink{ID: uuid.New(), ShortCode: "a", OriginalURL: "o", OwnerID: &owner}
+
+	create := mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, shortlink.ErrShortLinkAlreadyExists)
+	get := mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("a")).Return(existing, nil)
+	gomock.InOrder(create, get)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"a"}}, mockCache)
+	link, err := svc.Create(context.Background(), shortlink.CreateParams{OriginalURL: "o", OwnerID: &owner})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link != existing {
+		t.Fatalf("expected existing link, got %+v", link)
+	}
+}
+
+func TestShortLinkService_Create_CollisionWithOtherOwner_Retries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	owner, other := uuid.New(), uuid.New()
+	taken := &shortlink.ShortLink{ID: uuid.New(), ShortCode: "a", OriginalURL: "o", OwnerID: &other}
+
+	first := mockRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(nil, shortlink.ErrShortLinkAlreadyExists)
+	get := mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("a")).Return(taken, nil)
+	second := mockRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params shortlink.CreateParams) (*shortlink.ShortLink, error) {
+			return &shortlink.ShortLink{ShortCode: params.ShortCode}, nil
+		})
+	gomock.InOrder(first, get, second)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"a", "b"}}, mockCache)
+	link, err := svc.Create(context.Background(), shortlink.CreateParams{OriginalURL: "o", OwnerID: &owner})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link.ShortCode != "b" {
+		t.Fatalf("expected fallback code b, got %q", link.ShortCode)
+	}
+}
+
 func TestShortLinkService_Get_CacheHit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -136,10 +254,10 @@ func TestShortLinkService_Get_CacheHit(t *testing.T) {
 	//nolint: errcheck // empty model
 	bytes, _ := json.Marshal(model)
 
-	mockCache.EXPECT().Get(gomock.Any(), gomock.Eq("k")).Return(string(bytes), nil)
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Eq("/k")).Return(string(bytes), nil)
 
-	svc := services.NewShortLinkService(mockRepo, gen, mockCache)
-	got, err := svc.Get(context.Background(), "k")
+	svc := newShortLinkService(mockRepo, gen, mockCache)
+	got, err := svc.Get(context.Background(), "", "k")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -157,14 +275,14 @@ func TestShortLinkService_Get_CacheMiss_ReadsDBAndSetsCache(t *testing.T) {
 
 	gen := &seqGenerator{vals: []string{"unused"}}
 
-	mockCache.EXPECT().Get(gomock.Any(), gomock.Eq("k")).Return("", errors.New("not found"))
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Eq("/k")).Return("", errors.New("not found"))
 
-	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq("k")).Return(&shortlink.ShortLink{}, nil)
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).Return(&shortlink.ShortLink{}, nil)
 
-	mockCache.EXPECT().Set(gomock.Any(), gomock.Eq("k"), gomock.Any(), gomock.Any()).Return(nil)
+	mockCache.EXPECT().Set(gomock.Any(), gomock.Eq("/k"), gomock.Any(), gomock.Any()).Return(nil)
 
-	svc := services.NewShortLinkService(mockRepo, gen, mockCache)
-	got, err := svc.Get(context.Background(), "k")
+	svc := newShortLinkService(mockRepo, gen, mockCache)
+	got, err := svc.Get(context.Background(), "", "k")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -172,3 +290,1153 @@ func TestShortLinkService_Get_CacheMiss_ReadsDBAndSetsCache(t *testing.T) {
 		t.Fatalf("expected model from db, got nil")
 	}
 }
+
+func TestShortLinkService_Get_Expired_ReturnsLinkAndError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	gen := &seqGenerator{vals: []string{"unused"}}
+
+	expiresAt := time.Now().Add(-time.Minute)
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Eq("/k")).Return("", errors.New("not found"))
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).Return(&shortlink.ShortLink{
+		ExpiresAt:   &expiresAt,
+		FallbackURL: "https://fallback",
+	}, nil)
+
+	svc := newShortLinkService(mockRepo, gen, mockCache)
+	got, err := svc.Get(context.Background(), "", "k")
+	if !errors.Is(err, shortlink.ErrShortLinkExpired) {
+		t.Fatalf("expected ErrShortLinkExpired, got: %v", err)
+	}
+	if got == nil || got.FallbackURL != "https://fallback" {
+		t.Fatalf("expected expired link with fallback, got %+v", got)
+	}
+}
+
+func TestShortLinkService_Get_CacheTTLDoesNotOutliveExpiration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	gen := &seqGenerator{vals: []string{"unused"}}
+
+	expiresAt := time.Now().Add(time.Minute)
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Eq("/k")).Return("", errors.New("not found"))
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{ExpiresAt: &expiresAt}, nil)
+	mockCache.EXPECT().
+		Set(gomock.Any(), gomock.Eq("/k"), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ any, ttl time.Duration) error {
+			if ttl <= 0 || ttl > time.Minute {
+				t.Fatalf("expected ttl capped by expiration, got %v", ttl)
+			}
+			return nil
+		})
+
+	svc := newShortLinkService(mockRepo, gen, mockCache)
+	if _, err := svc.Get(context.Background(), "", "k"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestShortLinkService_ConsumeClick_Unlimited_SkipsCounters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	if err := svc.ConsumeClick(context.Background(), &shortlink.ShortLink{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestShortLinkService_ConsumeClick_CounterOverBudget_SkipsDB(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	maxClicks := int64(1)
+	link := &shortlink.ShortLink{ID: uuid.New(), MaxClicks: &maxClicks}
+
+	mockCache.EXPECT().Increment(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(2), nil)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	err := svc.ConsumeClick(context.Background(), link)
+	if !errors.Is(err, shortlink.ErrShortLinkExhausted) {
+		t.Fatalf("expected ErrShortLinkExhausted, got: %v", err)
+	}
+}
+
+func TestShortLinkService_ConsumeClick_CacheDown_FallsBackToDB(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	maxClicks := int64(1)
+	link := &shortlink.ShortLink{ID: uuid.New(), MaxClicks: &maxClicks}
+
+	mockCache.EXPECT().Increment(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), errors.New("down"))
+	mockRepo.EXPECT().ConsumeClick(gomock.Any(), gomock.Eq(link.ID)).Return(shortlink.ErrShortLinkExhausted)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	err := svc.ConsumeClick(context.Background(), link)
+	if !errors.Is(err, shortlink.ErrShortLinkExhausted) {
+		t.Fatalf("expected ErrShortLinkExhausted, got: %v", err)
+	}
+}
+
+func TestShortLinkService_Owned_ReportsRemainingBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	maxClicks := int64(5)
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{MaxClicks: &maxClicks, ClicksUsed: 2}, nil)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	link, err := svc.Owned(context.Background(), nil, "", "k")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	budget := link.ClickBudget()
+	if budget.Remaining == nil || *budget.Remaining != 3 {
+		t.Fatalf("expected 3 remaining clicks, got %+v", budget)
+	}
+}
+
+func TestShortLinkService_Owned_OtherOwner_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	owner, caller := uuid.New(), uuid.New()
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{OwnerID: &owner}, nil).Times(2)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	if _, err := svc.Owned(context.Background(), &caller, "", "k"); !errors.Is(err, shortlink.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for another owner, got: %v", err)
+	}
+	if _, err := svc.Owned(context.Background(), nil, "", "k"); !errors.Is(err, shortlink.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for anonymous caller, got: %v", err)
+	}
+}
+
+func TestShortLinkService_Create_HashesPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params shortlink.CreateParams) (*shortlink.ShortLink, error) {
+			if params.Password != "" {
+				t.Fatalf("plain password must not reach the repository")
+			}
+			if params.PasswordHash == "" || params.PasswordHash == "secret" {
+				t.Fatalf("expected password hash, got %q", params.PasswordHash)
+			}
+			return &shortlink.ShortLink{PasswordHash: params.PasswordHash}, nil
+		})
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"gen"}}, mockCache)
+	link, err := svc.Create(context.Background(), shortlink.CreateParams{OriginalURL: "o", Password: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !link.Protected() {
+		t.Fatalf("expected protected link")
+	}
+}
+
+func TestShortLinkService_Update_EvictsCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	id, owner := uuid.New(), uuid.New()
+	target := "https://new.example.com"
+	params := shortlink.UpdateParams{OriginalURL: &target}
+
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{ID: id, OwnerID: &owner}, nil)
+	update := mockRepo.EXPECT().Update(gomock.Any(), gomock.Eq(id), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, got shortlink.UpdateParams) (*shortlink.ShortLink, error) {
+			if *got.OriginalURL != target+"/" || *got.RawURL != target || got.TargetHash == nil ||
+				*got.TargetDomain != "new.example.com" {
+				t.Fatalf("unexpected update params: %+v", got)
+			}
+			return &shortlink.ShortLink{ID: id, OriginalURL: target}, nil
+		})
+	evict := mockCache.EXPECT().Delete(gomock.Any(), gomock.Eq("/k")).Return(nil)
+	gomock.InOrder(update, evict)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	link, err := svc.Update(context.Background(), &owner, "", "k", params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link.OriginalURL != target {
+		t.Fatalf("expected updated target, got %q", link.OriginalURL)
+	}
+}
+
+func TestShortLinkService_Delete_EvictsCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	id, owner := uuid.New(), uuid.New()
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{ID: id, OwnerID: &owner}, nil)
+	del := mockRepo.EXPECT().Delete(gomock.Any(), gomock.Eq(id)).Return(nil)
+	evict := mockCache.EXPECT().Delete(gomock.Any(), gomock.Eq("/k")).Return(nil)
+	gomock.InOrder(del, evict)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	if err := svc.Delete(context.Background(), &owner, "", "k"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestShortLinkService_Delete_AlreadyDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	owner := uuid.New()
+	deletedAt := time.Now()
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{DeletedAt: &deletedAt, OwnerID: &owner}, nil)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	if err := svc.Delete(context.Background(), &owner, "", "k"); !errors.Is(err, shortlink.ErrShortLinkDeleted) {
+		t.Fatalf("expected ErrShortLinkDeleted, got: %v", err)
+	}
+}
+
+func TestShortLinkService_Get_Deleted_FromCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	deletedAt := time.Now()
+	//nolint: errcheck // plain model
+	bytes, _ := json.Marshal(&shortlink.ShortLink{DeletedAt: &deletedAt})
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Eq("/k")).Return(string(bytes), nil)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	if _, err := svc.Get(context.Background(), "", "k"); !errors.Is(err, shortlink.ErrShortLinkDeleted) {
+		t.Fatalf("expected ErrShortLinkDeleted, got: %v", err)
+	}
+}
+
+func TestShortLinkService_Delete_AnonymousLink_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	caller := uuid.New()
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).Return(&shortlink.ShortLink{}, nil)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	if err := svc.Delete(context.Background(), &caller, "", "k"); !errors.Is(err, shortlink.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got: %v", err)
+	}
+}
+
+func TestShortLinkService_GetOrCreate_ReturnsExisting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	owner := uuid.New()
+	existing := &shortlink.ShortLink{ID: uuid.New()}
+
+	var hashes []string
+	mockRepo.EXPECT().GetDeduplicated(gomock.Any(), gomock.Eq(&owner), gomock.Eq(""), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *uuid.UUID, _, hash string) (*shortlink.ShortLink, error) {
+			hashes = append(hashes, hash)
+			return existing, nil
+		}).
+		Times(2)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	for _, target := range []string{"HTTPS://Example.com:443", "https://example.com/"} {
+		link, created, err := svc.GetOrCreate(context.Background(), shortlink.CreateParams{
+			OriginalURL: target,
+			OwnerID:     &owner,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if created || link != existing {
+			t.Fatalf("expected existing link, got created=%v link=%+v", created, link)
+		}
+	}
+
+	if hashes[0] != hashes[1] {
+		t.Fatalf("expected equivalent URLs to share a target hash, got %v", hashes)
+	}
+}
+
+func TestShortLinkService_GetOrCreate_CreatesDeduplicated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockRepo.EXPECT().GetDeduplicated(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, shortlink.ErrShortLinkNotFound)
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params shortlink.CreateParams) (*shortlink.ShortLink, error) {
+			if !params.Dedupe || params.TargetHash == "" {
+				t.Fatalf("expected dedupe params, got %+v", params)
+			}
+			return &shortlink.ShortLink{ShortCode: params.ShortCode}, nil
+		})
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"gen"}}, mockCache)
+	owner := uuid.New()
+	link, created, err := svc.GetOrCreate(context.Background(), shortlink.CreateParams{
+		OriginalURL: "https://ex",
+		OwnerID:     &owner,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created || link.ShortCode != "gen" {
+		t.Fatalf("expected new link, got created=%v link=%+v", created, link)
+	}
+}
+
+func TestShortLinkService_GetOrCreate_ConcurrentInsert_ReturnsWinner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	winner := &shortlink.ShortLink{ID: uuid.New()}
+	first := mockRepo.EXPECT().GetDeduplicated(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, shortlink.ErrShortLinkNotFound)
+	create := mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		Return(nil, shortlink.ErrTargetAlreadyShortened)
+	second := mockRepo.EXPECT().GetDeduplicated(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(winner, nil)
+	gomock.InOrder(first, create, second)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"gen"}}, mockCache)
+	owner := uuid.New()
+	link, created, err := svc.GetOrCreate(context.Background(), shortlink.CreateParams{
+		OriginalURL: "https://ex",
+		OwnerID:     &owner,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created || link != winner {
+		t.Fatalf("expected concurrent winner, got created=%v link=%+v", created, link)
+	}
+}
+
+func TestShortLinkService_GetOrCreate_RequiresOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	_, _, err := svc.GetOrCreate(context.Background(), shortlink.CreateParams{OriginalURL: "https://ex"})
+	if !errors.Is(err, shortlink.ErrDedupeRequiresOwner) {
+		t.Fatalf("expected ErrDedupeRequiresOwner, got: %v", err)
+	}
+}
+
+func TestShortLinkService_GetOrCreate_RetiresExhaustedLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	maxClicks := int64(1)
+	exhausted := &shortlink.ShortLink{ID: uuid.New(), MaxClicks: &maxClicks, ClicksUsed: 1}
+
+	lookup := mockRepo.EXPECT().GetDeduplicated(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(exhausted, nil)
+	retire := mockRepo.EXPECT().RetireDeduplicated(gomock.Any(), exhausted.ID).Return(nil)
+	create := mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params shortlink.CreateParams) (*shortlink.ShortLink, error) {
+			return &shortlink.ShortLink{ShortCode: params.ShortCode}, nil
+		})
+	gomock.InOrder(lookup, retire, create)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"gen"}}, mockCache)
+	owner := uuid.New()
+	link, created, err := svc.GetOrCreate(context.Background(), shortlink.CreateParams{
+		OriginalURL: "https://ex",
+		OwnerID:     &owner,
+		MaxClicks:   &maxClicks,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created || link.ShortCode != "gen" {
+		t.Fatalf("expected replacement link, got created=%v link=%+v", created, link)
+	}
+}
+
+func TestShortLinkService_GetOrCreate_DifferentOptions_Conflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	expiresAt := time.Now().Add(time.Hour)
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	existing := &shortlink.ShortLink{ID: uuid.New(), ExpiresAt: &expiresAt, PasswordHash: string(hash)}
+
+	mockRepo.EXPECT().GetDeduplicated(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(existing, nil).
+		AnyTimes()
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	owner := uuid.New()
+	later := expiresAt.Add(time.Hour)
+	cases := map[string]shortlink.CreateParams{
+		"expiresAt": {ExpiresAt: &later, Password: "secret"},
+		"password":  {ExpiresAt: &expiresAt, Password: "other"},
+		"fallback":  {ExpiresAt: &expiresAt, Password: "secret", FallbackURL: "https://fallback"},
+	}
+	for name, params := range cases {
+		params.OriginalURL = "https://ex"
+		params.OwnerID = &owner
+		if _, _, err := svc.GetOrCreate(context.Background(), params); !errors.Is(err, shortlink.ErrDedupeOptionsMismatch) {
+			t.Fatalf("%s: expected ErrDedupeOptionsMismatch, got: %v", name, err)
+		}
+	}
+
+	link, created, err := svc.GetOrCreate(context.Background(), shortlink.CreateParams{
+		OriginalURL: "https://ex",
+		OwnerID:     &owner,
+		ExpiresAt:   &expiresAt,
+		Password:    "secret",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created || link != existing {
+		t.Fatalf("expected existing link, got created=%v link=%+v", created, link)
+	}
+}
+
+func TestShortLinkService_CreateBatch_RetriesGeneratedCollisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	gen := &seqGenerator{vals: []string{"a", "b", "c"}}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().
+			CreateBatch(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params []shortlink.CreateParams) ([]shortlink.CreateResult, error) {
+				if len(params) != 3 || params[0].ShortCode != "a" || params[1].ShortCode != "custom" ||
+					params[2].ShortCode != "b" {
+					t.Fatalf("unexpected first batch: %+v", params)
+				}
+				return []shortlink.CreateResult{
+					{Err: shortlink.ErrShortLinkAlreadyExists},
+					{Err: shortlink.ErrShortLinkAlreadyExists},
+					{Link: &shortlink.ShortLink{ShortCode: "b"}},
+				}, nil
+			}),
+		mockRepo.EXPECT().
+			CreateBatch(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params []shortlink.CreateParams) ([]shortlink.CreateResult, error) {
+				if len(params) != 1 || params[0].ShortCode != "c" {
+					t.Fatalf("unexpected retry batch: %+v", params)
+				}
+				return []shortlink.CreateResult{{Link: &shortlink.ShortLink{ShortCode: "c"}}}, nil
+			}),
+	)
+
+	svc := newShortLinkService(mockRepo, gen, mockCache)
+
+	results, err := svc.CreateBatch(context.Background(), []shortlink.CreateParams{
+		{OriginalURL: "https://one"},
+		{ShortCode: "custom", OriginalURL: "https://two"},
+		{OriginalURL: "https://three"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Err != nil || results[0].Link.ShortCode != "c" {
+		t.Fatalf("expected retried item to get code c, got %+v", results[0])
+	}
+	if !errors.Is(results[1].Err, shortlink.ErrShortLinkAlreadyExists) {
+		t.Fatalf("expected custom collision error, got %+v", results[1])
+	}
+	if results[2].Err != nil || results[2].Link.ShortCode != "b" {
+		t.Fatalf("expected item to get code b, got %+v", results[2])
+	}
+}
+
+func TestShortLinkService_CreateBatch_FailedAfterAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockRepo.EXPECT().
+		CreateBatch(gomock.Any(), gomock.Any()).
+		Return([]shortlink.CreateResult{{Err: shortlink.ErrShortLinkAlreadyExists}}, nil).
+		Times(5)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"x"}}, mockCache)
+
+	results, err := svc.CreateBatch(context.Background(), []shortlink.CreateParams{{OriginalURL: "https://one"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(results[0].Err, shortlink.ErrShortLinkAlreadyExists) {
+		t.Fatalf("expected ErrShortLinkAlreadyExists, got %v", results[0].Err)
+	}
+}
+
+func TestShortLinkService_CreateBatch_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	dbErr := errors.New("db down")
+	mockRepo.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Return(nil, dbErr)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"x"}}, mockCache)
+
+	_, err := svc.CreateBatch(context.Background(), []shortlink.CreateParams{{OriginalURL: "https://one"}})
+	if !errors.Is(err, dbErr) {
+		t.Fatalf("expected db error, got %v", err)
+	}
+}
+
+func TestShortLinkService_List_AppliesDefaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockRepo.EXPECT().
+		List(gomock.Any(), gomock.Eq(shortlink.ListParams{
+			Filter: shortlink.ListFilter{TargetDomain: "example.com"},
+			Sort:   shortlink.ListSortCreatedAt,
+			Limit:  50,
+		})).
+		Return(&shortlink.ListPage{}, nil)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	_, err := svc.List(context.Background(), shortlink.ListParams{
+		Filter: shortlink.ListFilter{TargetDomain: "Example.COM"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestShortLinkService_List_CapsLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockRepo.EXPECT().
+		List(gomock.Any(), gomock.Eq(shortlink.ListParams{Sort: shortlink.ListSortTotalClicks, Limit: 1000})).
+		Return(&shortlink.ListPage{}, nil)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	_, err := svc.List(context.Background(), shortlink.ListParams{Sort: shortlink.ListSortTotalClicks, Limit: 100000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestShortLinkService_Create_CustomCodeRejectedByPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	svc := services.NewShortLinkService(
+		mockRepo,
+		&seqGenerator{vals: []string{"unused"}},
+		blockedPolicy{"admin": true},
+		1,
+		32,
+		allowAllURLs{},
+		mockCache,
+		false,
+	)
+
+	_, err := svc.Create(context.Background(), shortlink.CreateParams{ShortCode: "admin", OriginalURL: "o"})
+	if !errors.Is(err, shortlink.ErrShortCodeNotAllowed) {
+		t.Fatalf("expected ErrShortCodeNotAllowed, got %v", err)
+	}
+}
+
+func TestShortLinkService_Create_GeneratedCodeRejectedByPolicy_Retries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockRepo.EXPECT().
+		Create(gomock.Any(), createParams("fine", "o")).
+		Return(&shortlink.ShortLink{ShortCode: "fine"}, nil)
+
+	gen := &seqGenerator{vals: []string{"admin", "fine"}}
+	svc := services.NewShortLinkService(
+		mockRepo,
+		gen,
+		blockedPolicy{"admin": true},
+		1,
+		32,
+		allowAllURLs{},
+		mockCache,
+		false,
+	)
+
+	link, err := svc.Create(context.Background(), shortlink.CreateParams{OriginalURL: "o"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link.ShortCode != "fine" {
+		t.Fatalf("expected fine, got %q", link.ShortCode)
+	}
+	if len(gen.attempts) != 2 || gen.attempts[1] != 1 {
+		t.Fatalf("expected the rejected code to count as an attempt, got %v", gen.attempts)
+	}
+}
+
+func TestShortLinkService_CreateBatch_GeneratedCodeRejectedByPolicy_Retries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockRepo.EXPECT().
+		CreateBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params []shortlink.CreateParams) ([]shortlink.CreateResult, error) {
+			if len(params) != 1 || params[0].ShortCode != "fine" {
+				t.Fatalf("expected only the allowed code to reach the repository, got %+v", params)
+			}
+			return []shortlink.CreateResult{{Link: &shortlink.ShortLink{ShortCode: "fine"}}}, nil
+		})
+
+	gen := &seqGenerator{vals: []string{"admin", "fine"}}
+	svc := services.NewShortLinkService(
+		mockRepo,
+		gen,
+		blockedPolicy{"admin": true},
+		1,
+		32,
+		allowAllURLs{},
+		mockCache,
+		false,
+	)
+
+	results, err := svc.CreateBatch(context.Background(), []shortlink.CreateParams{{OriginalURL: "o"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Err != nil || results[0].Link.ShortCode != "fine" {
+		t.Fatalf("expected fine to be created, got %+v", results[0])
+	}
+}
+
+func TestShortLinkService_CreateBatch_CustomCodeRejectedByPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockRepo.EXPECT().
+		CreateBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params []shortlink.CreateParams) ([]shortlink.CreateResult, error) {
+			if len(params) != 1 || params[0].ShortCode != "fine" {
+				t.Fatalf("expected only the allowed code to reach the repository, got %+v", params)
+			}
+			return []shortlink.CreateResult{{Link: &shortlink.ShortLink{ShortCode: "fine"}}}, nil
+		})
+
+	svc := services.NewShortLinkService(
+		mockRepo,
+		&seqGenerator{vals: []string{"unused"}},
+		blockedPolicy{"admin": true},
+		1,
+		32,
+		allowAllURLs{},
+		mockCache,
+		false,
+	)
+
+	results, err := svc.CreateBatch(context.Background(), []shortlink.CreateParams{
+		{ShortCode: "admin", OriginalURL: "o"},
+		{ShortCode: "fine", OriginalURL: "o"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(results[0].Err, shortlink.ErrShortCodeNotAllowed) {
+		t.Fatalf("expected first item to be rejected, got %v", results[0].Err)
+	}
+	if results[1].Err != nil || results[1].Link == nil {
+		t.Fatalf("expected second item to be created, got %+v", results[1])
+	}
+}
+
+func TestShortLinkService_SuggestAliases_ChecksCandidatesInOneLookup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockRepo.EXPECT().
+		AvailableCodes(gomock.Any(), "go.example.com", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, codes []string) ([]string, error) {
+			want := []string{"my-promos", "my_promo", "my-promo-1", "my-promo-2"}
+			for i, code := range want {
+				if codes[i] != code {
+					t.Fatalf("expected candidates to start with %v, got %v", want, codes)
+				}
+			}
+			for _, code := range codes {
+				if code == "my-promo" || code == "mypromo" {
+					t.Fatalf("expected taken and blocked codes to be skipped, got %v", codes)
+				}
+			}
+			return []string{"my_promo", "my-promo-2", "my-promo-3", "my-promo-4", "my-promo-5", "my-promo1"}, nil
+		})
+
+	svc := services.NewShortLinkService(
+		mockRepo,
+		&seqGenerator{vals: []string{"unused"}},
+		blockedPolicy{"mypromo": true},
+		1,
+		32,
+		allowAllURLs{},
+		mockCache,
+		false,
+	)
+
+	suggestions, err := svc.SuggestAliases(context.Background(), "go.example.com", "my-promo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"my_promo", "my-promo-2", "my-promo-3", "my-promo-4", "my-promo-5"}
+	if fmt.Sprint(suggestions) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, suggestions)
+	}
+}
+
+func TestShortLinkService_SuggestAliases_RespectsConfiguredLengths(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockRepo.EXPECT().
+		AvailableCodes(gomock.Any(), "", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, codes []string) ([]string, error) {
+			for _, code := range codes {
+				if len(code) < 6 || len(code) > 7 {
+					t.Fatalf("expected candidates of 6 to 7 characters, got %v", codes)
+				}
+			}
+			return codes, nil
+		})
+
+	svc := services.NewShortLinkService(
+		mockRepo,
+		&seqGenerator{vals: []string{"unused"}},
+		allowAllPolicy{},
+		6,
+		7,
+		allowAllURLs{},
+		mockCache,
+		false,
+	)
+
+	suggestions, err := svc.SuggestAliases(context.Background(), "", "promos")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(suggestions) == 0 {
+		t.Fatalf("expected suggestions within the configured lengths")
+	}
+}
+
+func TestShortLinkService_Create_TargetRejectedByURLPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	svc := services.NewShortLinkService(
+		mockRepo, &seqGenerator{vals: []string{"gen"}}, allowAllPolicy{}, 1, 32, rejectURLs{}, mockCache, false,
+	)
+
+	_, err := svc.Create(context.Background(), shortlink.CreateParams{OriginalURL: "javascript:alert(1)"})
+	if !errors.Is(err, shortlink.ErrTargetNotAllowed) {
+		t.Fatalf("expected ErrTargetNotAllowed, got %v", err)
+	}
+}
+
+func TestShortLinkService_Create_DefaultsRedirectCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	var codes []int
+	mockRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params shortlink.CreateParams) (*shortlink.ShortLink, error) {
+			codes = append(codes, params.RedirectCode)
+			return &shortlink.ShortLink{RedirectCode: params.RedirectCode}, nil
+		}).
+		Times(2)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"a", "b"}}, mockCache)
+
+	for _, code := range []int{0, 308} {
+		params := shortlink.CreateParams{OriginalURL: "https://example.com", RedirectCode: code}
+		if _, err := svc.Create(context.Background(), params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if codes[0] != shortlink.DefaultRedirectCode || codes[1] != 308 {
+		t.Fatalf("expected redirect codes [302 308], got %v", codes)
+	}
+}
+
+type blockedURLs map[string]bool
+
+func (p blockedURLs) Check(_ context.Context, rawURL string) error {
+	if p[rawURL] {
+		return shortlink.ErrTargetNotAllowed
+	}
+	return nil
+}
+
+func TestShortLinkService_Create_NormalizesRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	mockRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params shortlink.CreateParams) (*shortlink.ShortLink, error) {
+			rule := params.Rules[0]
+			if rule.TargetURL != "https://example.de/" || rule.Countries[0] != "DE" {
+				t.Fatalf("unexpected rule: %+v", rule)
+			}
+			return &shortlink.ShortLink{Rules: params.Rules}, nil
+		})
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"gen"}}, mockCache)
+	_, err := svc.Create(context.Background(), shortlink.CreateParams{
+		OriginalURL: "https://example.com",
+		Rules:       []shortlink.Rule{{Name: "de", TargetURL: "HTTPS://Example.DE", Countries: []string{"de"}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestShortLinkService_Create_RuleTargetRejectedByURLPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	svc := services.NewShortLinkService(
+		mockRepo,
+		&seqGenerator{vals: []string{"gen"}},
+		allowAllPolicy{},
+		1,
+		32,
+		blockedURLs{"https://evil.example/": true},
+		mockCache,
+		false,
+	)
+
+	_, err := svc.Create(context.Background(), shortlink.CreateParams{
+		OriginalURL: "https://example.com",
+		Rules:       []shortlink.Rule{{Name: "bad", TargetURL: "https://evil.example"}},
+	})
+	if !errors.Is(err, shortlink.ErrTargetNotAllowed) {
+		t.Fatalf("expected ErrTargetNotAllowed, got %v", err)
+	}
+}
+
+func TestShortLinkService_Update_InvalidRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	owner := uuid.New()
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{ID: uuid.New(), OwnerID: &owner}, nil)
+
+	rules := []shortlink.Rule{{Name: "a", TargetURL: "https://a.example", OS: []string{"beos"}}}
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	_, err := svc.Update(context.Background(), &owner, "", "k", shortlink.UpdateParams{Rules: &rules})
+	if !errors.Is(err, shortlink.ErrInvalidRule) {
+		t.Fatalf("expected ErrInvalidRule, got %v", err)
+	}
+}
+
+func TestShortLinkService_Create_VariantTargetRejectedByURLPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	svc := services.NewShortLinkService(
+		mockRepo,
+		&seqGenerator{vals: []string{"gen"}},
+		allowAllPolicy{},
+		1,
+		32,
+		blockedURLs{"https://evil.example/": true},
+		mockCache,
+		false,
+	)
+
+	_, err := svc.Create(context.Background(), shortlink.CreateParams{
+		OriginalURL: "https://example.com",
+		Variants: []shortlink.Variant{
+			{Name: "a", TargetURL: "https://example.com/a", Weight: 1},
+			{Name: "b", TargetURL: "HTTPS://EVIL.example", Weight: 1},
+		},
+	})
+	if !errors.Is(err, shortlink.ErrTargetNotAllowed) {
+		t.Fatalf("expected ErrTargetNotAllowed, got %v", err)
+	}
+}
+
+func TestShortLinkService_Create_DeepLinkWebURLRejectedByURLPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	svc := services.NewShortLinkService(
+		mockRepo,
+		&seqGenerator{vals: []string{"gen"}},
+		allowAllPolicy{},
+		1,
+		32,
+		blockedURLs{"https://evil.example/": true},
+		mockCache,
+		false,
+	)
+
+	_, err := svc.Create(context.Background(), shortlink.CreateParams{
+		OriginalURL: "https://example.com",
+		DeepLink:    &shortlink.DeepLink{IOSURL: "myapp://item/1", WebURL: "https://EVIL.example"},
+	})
+	if !errors.Is(err, shortlink.ErrTargetNotAllowed) {
+		t.Fatalf("expected ErrTargetNotAllowed, got %v", err)
+	}
+}
+
+func TestShortLinkService_Update_InvalidDeepLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	owner := uuid.New()
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{ID: uuid.New(), OwnerID: &owner}, nil)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	_, err := svc.Update(context.Background(), &owner, "", "k", shortlink.UpdateParams{
+		DeepLink: &shortlink.DeepLink{AndroidURL: "javascript:alert(1)"},
+	})
+	if !errors.Is(err, shortlink.ErrInvalidDeepLink) {
+		t.Fatalf("expected ErrInvalidDeepLink, got %v", err)
+	}
+}
+
+func TestShortLinkService_AttachTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	id, owner := uuid.New(), uuid.New()
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{ID: id, OwnerID: &owner, Tags: []string{"promo"}}, nil)
+	attach := mockRepo.EXPECT().
+		AttachTags(gomock.Any(), gomock.Eq(id), gomock.Eq(owner), gomock.Eq([]string{"spring", "promo"})).
+		Return(nil)
+	evict := mockCache.EXPECT().Delete(gomock.Any(), gomock.Eq("/k")).Return(nil)
+	gomock.InOrder(attach, evict)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	link, err := svc.AttachTags(context.Background(), &owner, "", "k", []string{"Spring", "promo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"promo", "spring"}; !slices.Equal(link.Tags, want) {
+		t.Fatalf("expected tags %v, got %v", want, link.Tags)
+	}
+}
+
+func TestShortLinkService_AttachTags_TooMany(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	owner := uuid.New()
+	tags := make([]string, shortlink.MaxTags)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("tag-%d", i)
+	}
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{OwnerID: &owner, Tags: tags}, nil)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	_, err := svc.AttachTags(context.Background(), &owner, "", "k", []string{"one-more"})
+	if !errors.Is(err, shortlink.ErrInvalidTag) {
+		t.Fatalf("expected ErrInvalidTag, got: %v", err)
+	}
+}
+
+func TestShortLinkService_AttachTags_AnotherOwner_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	owner, caller := uuid.New(), uuid.New()
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{OwnerID: &owner}, nil)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	_, err := svc.AttachTags(context.Background(), &caller, "", "k", []string{"promo"})
+	if !errors.Is(err, shortlink.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got: %v", err)
+	}
+}
+
+func TestShortLinkService_DetachTag_NotAttached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	id, owner := uuid.New(), uuid.New()
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Eq(""), gomock.Eq("k")).
+		Return(&shortlink.ShortLink{ID: id, OwnerID: &owner}, nil)
+	mockRepo.EXPECT().DetachTag(gomock.Any(), gomock.Eq(id), gomock.Eq(owner), gomock.Eq("promo")).
+		Return(shortlink.ErrTagNotFound)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	if err := svc.DetachTag(context.Background(), &owner, "", "k", "Promo"); !errors.Is(err, shortlink.ErrTagNotFound) {
+		t.Fatalf("expected ErrTagNotFound, got: %v", err)
+	}
+}
+
+func TestShortLinkService_ConsumeClick_DBFailure_ReturnsCounterSlot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockCache := mocks.NewMockCache(ctrl)
+
+	maxClicks := int64(1)
+	link := &shortlink.ShortLink{ID: uuid.New(), MaxClicks: &maxClicks}
+	dbErr := errors.New("connection reset")
+
+	mockCache.EXPECT().Increment(gomock.Any(), gomock.Eq("clicks:"+link.ID.String()), gomock.Any()).Return(int64(1), nil)
+	mockRepo.EXPECT().ConsumeClick(gomock.Any(), gomock.Eq(link.ID)).Return(dbErr)
+	mockCache.EXPECT().Decrement(gomock.Any(), gomock.Eq("clicks:"+link.ID.String())).Return(nil)
+
+	svc := newShortLinkService(mockRepo, &seqGenerator{vals: []string{"unused"}}, mockCache)
+	if err := svc.ConsumeClick(context.Background(), link); !errors.Is(err, dbErr) {
+		t.Fatalf("expected the DB error, got: %v", err)
+	}
+}
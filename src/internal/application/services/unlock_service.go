@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"shortener/src/internal/application/config"
+	"shortener/src/internal/application/contracts"
+	shortlink "shortener/src/internal/domain/short_link"
+	"shortener/src/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const unlockAttemptsKeyPrefix = "unlock:"
+
+type UnlockService struct {
+	cache          contracts.Cache
+	secret         []byte
+	cookieTTL      time.Duration
+	maxAttempts    int64
+	attemptsWindow time.Duration
+}
+
+func NewUnlockService(cache contracts.Cache, cfg config.UnlockConfig) (*UnlockService, error) {
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("generate unlock secret: %w", err)
+		}
+		logger.Info("UNLOCK_SECRET is not set, unlock cookies will not survive restarts")
+	}
+
+	return &UnlockService{
+		cache:          cache,
+		secret:         secret,
+		cookieTTL:      cfg.CookieTTL,
+		maxAttempts:    cfg.MaxAttempts,
+		attemptsWindow: cfg.AttemptsWindow,
+	}, nil
+}
+
+func (s *UnlockService) Unlock(
+	ctx context.Context,
+	link *shortlink.ShortLink,
+	password, clientIP string,
+) (*shortlink.UnlockToken, error) {
+	key := unlockAttemptsKeyPrefix + link.ID.String() + ":" + clientIP
+	attempts, err := s.cache.Increment(ctx, key, s.attemptsWindow)
+	if err != nil {
+		logger.Error("failed to increment unlock attempts", "err", err)
+	} else if attempts > s.maxAttempts {
+		return nil, shortlink.ErrTooManyUnlockAttempts
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return nil, shortlink.ErrInvalidPassword
+		}
+
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.cookieTTL)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return &shortlink.UnlockToken{
+		Value:     expires + "." + s.sign(link, expires),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *UnlockService) Verify(link *shortlink.ShortLink, token string) bool {
+	expires, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() >= expiresUnix {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(s.sign(link, expires)))
+}
+
+func (s *UnlockService) sign(link *shortlink.ShortLink, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(link.ID.String()))
+	mac.Write([]byte{0})
+	mac.Write([]byte(link.PasswordHash))
+	mac.Write([]byte{0})
+	mac.Write([]byte(expires))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+func passwordMatches(hash, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"shortener/src/internal/application/config"
+	"shortener/src/internal/application/services"
+	"shortener/src/internal/application/services/mocks"
+	shortlink "shortener/src/internal/domain/short_link"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testUnlockConfig = config.UnlockConfig{
+	Secret:         "secret",
+	CookieTTL:      time.Minute,
+	MaxAttempts:    3,
+	AttemptsWindow: time.Minute,
+}
+
+func protectedLink(t *testing.T, password string) *shortlink.ShortLink {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+
+	return &shortlink.ShortLink{ID: uuid.New(), ShortCode: "abc", PasswordHash: string(hash)}
+}
+
+func TestUnlockService_Unlock_CorrectPassword_IssuesVerifiableToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockCache.EXPECT().Increment(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+	svc, err := services.NewUnlockService(mockCache, testUnlockConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	link := protectedLink(t, "pass")
+	token, err := svc.Unlock(context.Background(), link, "pass", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !svc.Verify(link, token.Value) {
+		t.Fatalf("expected token to be valid")
+	}
+
+	other := protectedLink(t, "pass")
+	if svc.Verify(other, token.Value) {
+		t.Fatalf("expected token to be bound to the link")
+	}
+
+	if svc.Verify(link, token.Value+"x") {
+		t.Fatalf("expected tampered token to be rejected")
+	}
+}
+
+func TestUnlockService_Unlock_WrongPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockCache.EXPECT().Increment(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+	svc, err := services.NewUnlockService(mockCache, testUnlockConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = svc.Unlock(context.Background(), protectedLink(t, "pass"), "wrong", "127.0.0.1")
+	if !errors.Is(err, shortlink.ErrInvalidPassword) {
+		t.Fatalf("expected ErrInvalidPassword, got: %v", err)
+	}
+}
+
+func TestUnlockService_Unlock_RateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockCache.EXPECT().Increment(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(4), nil)
+
+	svc, err := services.NewUnlockService(mockCache, testUnlockConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = svc.Unlock(context.Background(), protectedLink(t, "pass"), "pass", "127.0.0.1")
+	if !errors.Is(err, shortlink.ErrTooManyUnlockAttempts) {
+		t.Fatalf("expected ErrTooManyUnlockAttempts, got: %v", err)
+	}
+}
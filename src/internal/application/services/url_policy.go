@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"shortener/src/internal/application/config"
+	"shortener/src/internal/application/contracts"
+	brandeddomain "shortener/src/internal/domain/branded_domain"
+	shortlink "shortener/src/internal/domain/short_link"
+	"strings"
+)
+
+const redirectPathPrefix = "/s/"
+
+// knownShorteners are public URL shorteners; linking to them would hide the real target behind a chain.
+var knownShorteners = []string{
+	"bit.ly", "bitly.com", "buff.ly", "clck.ru", "cutt.ly", "goo.gl", "is.gd", "lnkd.in", "ow.ly",
+	"rb.gy", "rebrand.ly", "s.id", "shorturl.at", "t.co", "t.ly", "tiny.cc", "tinyurl.com", "v.gd",
+}
+
+type URLPolicy struct {
+	schemes       map[string]bool
+	publicHosts   map[string]bool
+	domainService brandeddomain.DomainService
+	lists         contracts.DomainLists
+}
+
+func NewURLPolicy(
+	cfg config.URLPolicyConfig,
+	domainService brandeddomain.DomainService,
+	lists contracts.DomainLists,
+) *URLPolicy {
+	policy := &URLPolicy{
+		schemes:       make(map[string]bool, len(cfg.AllowedSchemes)),
+		publicHosts:   make(map[string]bool, len(cfg.PublicHosts)),
+		domainService: domainService,
+		lists:         lists,
+	}
+
+	for _, scheme := range cfg.AllowedSchemes {
+		policy.schemes[strings.ToLower(strings.TrimSpace(scheme))] = true
+	}
+
+	for _, host := range cfg.PublicHosts {
+		policy.publicHosts[normalizeHost(host)] = true
+	}
+
+	return policy
+}
+
+func (p *URLPolicy) Check(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", shortlink.ErrTargetNotAllowed, err)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if !p.schemes[scheme] {
+		return fmt.Errorf("%w: scheme %q is not allowed", shortlink.ErrTargetNotAllowed, scheme)
+	}
+
+	host := normalizeHost(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("%w: host is required", shortlink.ErrTargetNotAllowed)
+	}
+
+	allow, block := p.lists.Lists()
+	if matchesDomain(host, block) {
+		return fmt.Errorf("%w: domain %q is blocked", shortlink.ErrTargetNotAllowed, host)
+	}
+
+	allowlisted := matchesDomain(host, allow)
+	if len(allow) > 0 && !allowlisted {
+		return fmt.Errorf("%w: domain %q is not in the allowlist", shortlink.ErrTargetNotAllowed, host)
+	}
+
+	if !allowlisted && matchesDomain(host, knownShorteners) {
+		return fmt.Errorf("%w: %q is another URL shortener", shortlink.ErrTargetNotAllowed, host)
+	}
+
+	if strings.HasPrefix(u.Path, redirectPathPrefix) {
+		own, err := p.ownHost(ctx, host)
+		if err != nil {
+			return err
+		}
+
+		if own {
+			return fmt.Errorf("%w: link points back to this shortener", shortlink.ErrTargetNotAllowed)
+		}
+	}
+
+	return nil
+}
+
+func (p *URLPolicy) ownHost(ctx context.Context, host string) (bool, error) {
+	if p.publicHosts[host] {
+		return true, nil
+	}
+
+	domain, err := p.domainService.Resolve(ctx, host)
+	if err != nil {
+		return false, err
+	}
+
+	return domain != "", nil
+}
+
+// matchesDomain reports whether host is one of the domains or a subdomain of one.
+func matchesDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"shortener/src/internal/application/config"
+	"shortener/src/internal/application/services"
+	"shortener/src/internal/application/services/mocks"
+	brandeddomain "shortener/src/internal/domain/branded_domain"
+	shortlink "shortener/src/internal/domain/short_link"
+
+	"go.uber.org/mock/gomock"
+)
+
+type staticLists struct {
+	allow []string
+	block []string
+}
+
+func (l staticLists) Lists() (allow, block []string) {
+	return l.allow, l.block
+}
+
+func newURLPolicy(t *testing.T, lists staticLists) *services.URLPolicy {
+	ctrl := gomock.NewController(t)
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockCache.EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string) (string, error) {
+			if key == "domain:go.brand.com" {
+				return "1", nil
+			}
+			return "", errors.New("not found")
+		}).
+		AnyTimes()
+
+	mockRepo := mocks.NewMockDomainRepository(ctrl)
+	mockRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, brandeddomain.ErrDomainNotFound).AnyTimes()
+
+	domainService := services.NewDomainService(mockRepo, mockCache, []string{"sho.rt"})
+	cfg := config.URLPolicyConfig{
+		AllowedSchemes: []string{"http", "https"},
+		PublicHosts:    []string{"sho.rt"},
+	}
+
+	return services.NewURLPolicy(cfg, domainService, lists)
+}
+
+func TestURLPolicy_Check(t *testing.T) {
+	policy := newURLPolicy(t, staticLists{block: []string{"evil.com"}})
+
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{url: "https://example.com/page", allowed: true},
+		{url: "HTTP://Example.com", allowed: true},
+		{url: "https://sho.rt/swagger/index.html", allowed: true},
+		{url: "javascript:alert(1)", allowed: false},
+		{url: "data:text/html,hi", allowed: false},
+		{url: "file:///etc/passwd", allowed: false},
+		{url: "https://evil.com", allowed: false},
+		{url: "https://login.evil.com/x", allowed: false},
+		{url: "https://notevil.com", allowed: true},
+		{url: "https://bit.ly/abc", allowed: false},
+		{url: "https://sho.rt/s/abc", allowed: false},
+		{url: "https://SHO.RT:8080/s/abc", allowed: false},
+		{url: "https://go.brand.com/s/abc", allowed: false},
+	}
+
+	for _, tt := range tests {
+		err := policy.Check(context.Background(), tt.url)
+		if tt.allowed && err != nil {
+			t.Errorf("expected %q to be allowed, got %v", tt.url, err)
+		}
+		if !tt.allowed && !errors.Is(err, shortlink.ErrTargetNotAllowed) {
+			t.Errorf("expected %q to be rejected, got %v", tt.url, err)
+		}
+	}
+}
+
+func TestURLPolicy_Allowlist(t *testing.T) {
+	policy := newURLPolicy(t, staticLists{allow: []string{"example.com", "bit.ly"}})
+
+	if err := policy.Check(context.Background(), "https://docs.example.com"); err != nil {
+		t.Fatalf("expected allowlisted subdomain to pass, got %v", err)
+	}
+	if err := policy.Check(context.Background(), "https://bit.ly/abc"); err != nil {
+		t.Fatalf("expected allowlisted shortener to pass, got %v", err)
+	}
+	if err := policy.Check(context.Background(), "https://other.com"); !errors.Is(err, shortlink.ErrTargetNotAllowed) {
+		t.Fatalf("expected domain outside the allowlist to be rejected, got %v", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"shortener/src/internal/application/contracts"
 	"shortener/src/internal/domain/visit"
+
+	"github.com/google/uuid"
 )
 
 type VisitService struct {
@@ -32,14 +34,63 @@ func (s *VisitService) CreateBatch(ctx context.Context, visits []visit.Visit) {
 	s.visitRepository.CreateBatch(ctx, visits)
 }
 
-func (s *VisitService) ByDayAnalytics(ctx context.Context, shortURL string) ([]visit.PeriodCount, error) {
-	return s.visitRepository.AnalyticsAggregatedByDay(ctx, shortURL)
+func (s *VisitService) ByDayAnalytics(ctx context.Context, linkIDs ...uuid.UUID) ([]visit.PeriodCount, error) {
+	return s.visitRepository.AnalyticsAggregatedByDay(ctx, linkIDs...)
+}
+
+func (s *VisitService) ByMonthAnalytics(ctx context.Context, linkIDs ...uuid.UUID) ([]visit.PeriodCount, error) {
+	return s.visitRepository.AnalyticsAggregatedByMonth(ctx, linkIDs...)
+}
+
+func (s *VisitService) ByUserAgentAnalytics(ctx context.Context, linkIDs ...uuid.UUID) ([]visit.UserAgentCount, error) {
+	return s.visitRepository.AnalyticsAggregatedByUserAgent(ctx, linkIDs...)
 }
 
-func (s *VisitService) ByMonthAnalytics(ctx context.Context, shortURL string) ([]visit.PeriodCount, error) {
-	return s.visitRepository.AnalyticsAggregatedByMonth(ctx, shortURL)
+func (s *VisitService) BySourceAnalytics(ctx context.Context, linkIDs ...uuid.UUID) ([]visit.SourceCount, error) {
+	return s.visitRepository.AnalyticsAggregatedBySource(ctx, linkIDs...)
 }
 
-func (s *VisitService) ByUserAgentAnalytics(ctx context.Context, shortURL string) ([]visit.UserAgentCount, error) {
-	return s.visitRepository.AnalyticsAggregatedByUserAgent(ctx, shortURL)
+// TotalVisits reads the link's visit counter from storage; the copy on a cached link lags behind it.
+func (s *VisitService) TotalVisits(ctx context.Context, linkID uuid.UUID) (int64, error) {
+	return s.visitRepository.TotalVisits(ctx, linkID)
+}
+
+// ByVariantAnalytics counts visits per split variant. The link's current variants come first in their order,
+// including those without visits yet, followed by variants removed from the split since.
+func (s *VisitService) ByVariantAnalytics(
+	ctx context.Context,
+	linkID uuid.UUID,
+	variants []string,
+) ([]visit.VariantCount, error) {
+	counts, err := s.visitRepository.AnalyticsAggregatedByVariant(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]int64, len(counts))
+	var total int64
+	for _, count := range counts {
+		byName[count.Variant] = count.Count
+		total += count.Count
+	}
+
+	result := make([]visit.VariantCount, 0, len(variants)+len(counts))
+	for _, name := range variants {
+		result = append(result, visit.VariantCount{Variant: name, Count: byName[name]})
+		delete(byName, name)
+	}
+
+	for _, count := range counts {
+		if _, ok := byName[count.Variant]; ok {
+			result = append(result, visit.VariantCount{Variant: count.Variant, Count: count.Count})
+		}
+	}
+
+	if total > 0 {
+		for i := range result {
+			result[i].Share = float64(result[i].Count) / float64(total)
+		}
+	}
+
+	return result, nil
 }
@@ -9,6 +9,7 @@ import (
 	"shortener/src/internal/application/services/mocks"
 	"shortener/src/internal/domain/visit"
 
+	"github.com/google/uuid"
 	"go.uber.org/mock/gomock"
 )
 
@@ -66,23 +67,62 @@ func TestVisitService_AnalyticsDelegation(t *testing.T) {
 	mockRepo := mocks.NewMockVisitRepository(ctrl)
 	mockProducer := mocks.NewMockMessageProducer(ctrl)
 
+	linkID := uuid.New()
 	byDay := []visit.PeriodCount{{}}
 	byMonth := []visit.PeriodCount{{}}
 	byUA := []visit.UserAgentCount{{}}
 
-	mockRepo.EXPECT().AnalyticsAggregatedByDay(gomock.Any(), gomock.Eq("k")).Return(byDay, nil)
-	mockRepo.EXPECT().AnalyticsAggregatedByMonth(gomock.Any(), gomock.Eq("k")).Return(byMonth, nil)
-	mockRepo.EXPECT().AnalyticsAggregatedByUserAgent(gomock.Any(), gomock.Eq("k")).Return(byUA, nil)
+	mockRepo.EXPECT().AnalyticsAggregatedByDay(gomock.Any(), gomock.Eq(linkID)).Return(byDay, nil)
+	mockRepo.EXPECT().AnalyticsAggregatedByMonth(gomock.Any(), gomock.Eq(linkID)).Return(byMonth, nil)
+	mockRepo.EXPECT().AnalyticsAggregatedByUserAgent(gomock.Any(), gomock.Eq(linkID)).Return(byUA, nil)
+	mockRepo.EXPECT().TotalVisits(gomock.Any(), gomock.Eq(linkID)).Return(int64(7), nil)
 
 	svc := services.NewVisitService(mockRepo, mockProducer)
 
-	if _, err := svc.ByDayAnalytics(context.Background(), "k"); err != nil {
+	if _, err := svc.ByDayAnalytics(context.Background(), linkID); err != nil {
 		t.Fatalf("ByDayAnalytics error: %v", err)
 	}
-	if _, err := svc.ByMonthAnalytics(context.Background(), "k"); err != nil {
+	if _, err := svc.ByMonthAnalytics(context.Background(), linkID); err != nil {
 		t.Fatalf("ByMonthAnalytics error: %v", err)
 	}
-	if _, err := svc.ByUserAgentAnalytics(context.Background(), "k"); err != nil {
+	if _, err := svc.ByUserAgentAnalytics(context.Background(), linkID); err != nil {
 		t.Fatalf("ByUserAgentAnalytics error: %v", err)
 	}
+	if total, err := svc.TotalVisits(context.Background(), linkID); err != nil || total != 7 {
+		t.Fatalf("TotalVisits returned %d, %v", total, err)
+	}
+}
+
+func TestVisitService_ByVariantAnalytics_ComparesVariants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockVisitRepository(ctrl)
+	mockProducer := mocks.NewMockMessageProducer(ctrl)
+
+	linkID := uuid.New()
+	mockRepo.EXPECT().AnalyticsAggregatedByVariant(gomock.Any(), gomock.Eq(linkID)).Return([]visit.VariantCount{
+		{Variant: "b", Count: 30},
+		{Variant: "old", Count: 10},
+	}, nil)
+
+	svc := services.NewVisitService(mockRepo, mockProducer)
+	res, err := svc.ByVariantAnalytics(context.Background(), linkID, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []visit.VariantCount{
+		{Variant: "a", Count: 0, Share: 0},
+		{Variant: "b", Count: 30, Share: 0.75},
+		{Variant: "old", Count: 10, Share: 0.25},
+	}
+	if len(res) != len(want) {
+		t.Fatalf("got %+v, want %+v", res, want)
+	}
+	for i := range want {
+		if res[i] != want[i] {
+			t.Fatalf("got %+v, want %+v", res, want)
+		}
+	}
 }
@@ -0,0 +1,6 @@
+package apikey
+
+import "errors"
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
+var ErrInvalidAPIKey = errors.New("invalid api key")
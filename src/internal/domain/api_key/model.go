@@ -0,0 +1,21 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKey struct {
+	ID         uuid.UUID
+	OwnerID    uuid.UUID
+	Name       string
+	Prefix     string
+	SecretHash string
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+}
+
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,13 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key APIKey) error
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
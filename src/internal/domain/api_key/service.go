@@ -0,0 +1,14 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyService interface {
+	// Issue returns the stored key together with its plain token, which is never persisted.
+	Issue(ctx context.Context, ownerID *uuid.UUID, name string) (*APIKey, string, error)
+	Authenticate(ctx context.Context, token string) (*APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,7 @@
+package brandeddomain
+
+import "errors"
+
+var ErrDomainNotFound = errors.New("domain not found")
+var ErrDomainAlreadyExists = errors.New("domain already exists")
+var ErrDomainInUse = errors.New("domain has short links")
@@ -0,0 +1,8 @@
+package brandeddomain
+
+import "time"
+
+type Domain struct {
+	Host      string
+	CreatedAt time.Time
+}
@@ -0,0 +1,10 @@
+package brandeddomain
+
+import "context"
+
+type DomainRepository interface {
+	Create(ctx context.Context, host string) (*Domain, error)
+	Get(ctx context.Context, host string) (*Domain, error)
+	List(ctx context.Context) ([]Domain, error)
+	Delete(ctx context.Context, host string) error
+}
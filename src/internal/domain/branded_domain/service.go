@@ -0,0 +1,11 @@
+package brandeddomain
+
+import "context"
+
+type DomainService interface {
+	Register(ctx context.Context, host string) (*Domain, error)
+	List(ctx context.Context) ([]Domain, error)
+	Delete(ctx context.Context, host string) error
+	// Resolve maps a request host to a registered domain, or to "" for the default domain.
+	Resolve(ctx context.Context, host string) (string, error)
+}
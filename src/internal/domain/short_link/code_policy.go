@@ -0,0 +1,7 @@
+package shortlink
+
+// CodePolicy decides whether a short code may be handed out, either generated or chosen as a custom alias.
+type CodePolicy interface {
+	// Check returns an error wrapping ErrShortCodeNotAllowed if the code must not be used.
+	Check(code string) error
+}
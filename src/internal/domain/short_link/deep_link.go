@@ -0,0 +1,67 @@
+package shortlink
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// DeepLink opens the link in a mobile app. IOSURL is a custom scheme URI or a universal link, AndroidURL
+// a custom scheme URI or an intent:// URI. Visitors whose app does not open go to WebURL, or to the link's
+// usual target if it is empty.
+type DeepLink struct {
+	IOSURL     string `json:"iosURL,omitempty"`
+	AndroidURL string `json:"androidURL,omitempty"`
+	WebURL     string `json:"webURL,omitempty"`
+}
+
+// unsafeAppSchemes run code in the page that opens them instead of handing off to an app.
+var unsafeAppSchemes = []string{"javascript", "data", "vbscript", "blob", "file", "about"}
+
+func (d *DeepLink) Empty() bool {
+	return d.IOSURL == "" && d.AndroidURL == "" && d.WebURL == ""
+}
+
+// Handoff returns the app URL for the visitor's OS and the web URL to fall back to. The app URL is empty
+// for platforms without an app; such visitors are redirected to target as usual.
+func (d *DeepLink) Handoff(os, target string) (app, web string) {
+	switch os {
+	case OSiOS:
+		app = d.IOSURL
+	case OSAndroid:
+		app = d.AndroidURL
+	}
+
+	web = target
+	if d.WebURL != "" {
+		web = d.WebURL
+	}
+
+	return app, web
+}
+
+// Validate requires an app URL for at least one platform and rejects app URLs that are not absolute or
+// that would run script. Errors wrap ErrInvalidDeepLink.
+func (d *DeepLink) Validate() error {
+	if d.IOSURL == "" && d.AndroidURL == "" {
+		return fmt.Errorf("%w: an iOS or Android URL is required", ErrInvalidDeepLink)
+	}
+
+	for _, appURL := range []string{d.IOSURL, d.AndroidURL} {
+		if appURL == "" {
+			continue
+		}
+
+		u, err := url.Parse(appURL)
+		if err != nil || u.Scheme == "" {
+			return fmt.Errorf("%w: %q is not an absolute URI", ErrInvalidDeepLink, appURL)
+		}
+
+		if slices.Contains(unsafeAppSchemes, strings.ToLower(u.Scheme)) {
+			return fmt.Errorf("%w: scheme %q is not allowed", ErrInvalidDeepLink, u.Scheme)
+		}
+	}
+
+	return nil
+}
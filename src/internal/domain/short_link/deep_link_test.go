@@ -0,0 +1,60 @@
+package shortlink_test
+
+import (
+	"errors"
+	"testing"
+
+	shortlink "shortener/src/internal/domain/short_link"
+)
+
+func TestDeepLink_Handoff(t *testing.T) {
+	deepLink := shortlink.DeepLink{IOSURL: "myapp://item/1", WebURL: "https://example.com/app"}
+
+	tests := []struct {
+		name    string
+		os      string
+		wantApp string
+		wantWeb string
+	}{
+		{name: "ios opens the app", os: shortlink.OSiOS, wantApp: "myapp://item/1", wantWeb: "https://example.com/app"},
+		{name: "android has no app", os: shortlink.OSAndroid, wantWeb: "https://example.com/app"},
+		{name: "desktop has no app", os: shortlink.OSWindows, wantWeb: "https://example.com/app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, web := deepLink.Handoff(tt.os, "https://example.com/target")
+			if app != tt.wantApp || web != tt.wantWeb {
+				t.Fatalf("got (%q, %q), want (%q, %q)", app, web, tt.wantApp, tt.wantWeb)
+			}
+		})
+	}
+
+	withoutWeb := shortlink.DeepLink{AndroidURL: "intent://item/1#Intent;scheme=myapp;end"}
+	_, web := withoutWeb.Handoff(shortlink.OSAndroid, "https://example.com/target")
+	if web != "https://example.com/target" {
+		t.Fatalf("expected the link target as the web URL, got %q", web)
+	}
+}
+
+func TestDeepLink_Validate(t *testing.T) {
+	valid := shortlink.DeepLink{IOSURL: "https://app.example.com/item/1", AndroidURL: "myapp://item/1"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalid := map[string]shortlink.DeepLink{
+		"no app URL":        {WebURL: "https://example.com"},
+		"relative URL":      {IOSURL: "/item/1"},
+		"javascript scheme": {AndroidURL: "JavaScript:alert(1)"},
+		"data scheme":       {IOSURL: "data:text/html,<script>alert(1)</script>"},
+	}
+
+	for name, deepLink := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := deepLink.Validate(); !errors.Is(err, shortlink.ErrInvalidDeepLink) {
+				t.Fatalf("expected ErrInvalidDeepLink, got %v", err)
+			}
+		})
+	}
+}
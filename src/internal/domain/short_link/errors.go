@@ -3,4 +3,23 @@ package shortlink
 import "errors"
 
 var ErrShortLinkAlreadyExists = errors.New("short link already exists")
+var ErrTargetAlreadyShortened = errors.New("target already shortened by this owner")
+var ErrDedupeOptionsMismatch = errors.New("target already shortened by this owner with different options")
+var ErrDedupeRequiresOwner = errors.New("dedupe requires an api key")
+var ErrShortCodeNotAllowed = errors.New("short code is not allowed")
+var ErrTargetNotAllowed = errors.New("target URL is not allowed")
+var ErrUnknownDomain = errors.New("domain is not registered")
 var ErrShortLinkNotFound = errors.New("short link not found")
+var ErrInvalidPassthrough = errors.New("invalid passthrough path or query")
+var ErrInvalidRule = errors.New("invalid redirect rule")
+var ErrInvalidVariant = errors.New("invalid split variant")
+var ErrInvalidDeepLink = errors.New("invalid deep link")
+var ErrInvalidTag = errors.New("invalid tag")
+var ErrTagNotFound = errors.New("tag is not attached to the short link")
+var ErrMetadataUnavailable = errors.New("target page metadata unavailable")
+var ErrShortLinkExpired = errors.New("short link expired")
+var ErrShortLinkDeleted = errors.New("short link deleted")
+var ErrShortLinkExhausted = errors.New("short link click limit reached")
+var ErrForbidden = errors.New("short link belongs to another owner")
+var ErrInvalidPassword = errors.New("invalid password")
+var ErrTooManyUnlockAttempts = errors.New("too many unlock attempts")
@@ -0,0 +1,40 @@
+package shortlink
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Metadata describes the target page. It is fetched in the background after the link is created or its target
+// changes, so a link has none until the fetch succeeds.
+type Metadata struct {
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	ImageURL    string    `json:"imageURL,omitempty"`
+	FaviconURL  string    `json:"faviconURL,omitempty"`
+	FetchedAt   time.Time `json:"fetchedAt"`
+}
+
+// MetadataRequest asks for the metadata of a link's target. URL pins the target the request was made for,
+// so metadata fetched after the target has changed is dropped.
+type MetadataRequest struct {
+	LinkID    uuid.UUID `json:"linkID"`
+	Domain    string    `json:"domain,omitempty"`
+	ShortCode string    `json:"shortCode"`
+	URL       string    `json:"url"`
+}
+
+// MetadataFetcher reads the metadata of a page. Errors wrap ErrMetadataUnavailable when the target
+// answers with something other than an HTML page.
+type MetadataFetcher interface {
+	Fetch(ctx context.Context, targetURL string) (*Metadata, error)
+}
+
+type MetadataService interface {
+	// Request queues fetching the target metadata of the links.
+	Request(ctx context.Context, links ...ShortLink) error
+	// Fetch fetches and stores the metadata of a queued request.
+	Fetch(ctx context.Context, req MetadataRequest) error
+}
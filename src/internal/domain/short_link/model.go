@@ -6,11 +6,200 @@ import (
 	"github.com/google/uuid"
 )
 
-const ShortLinkLength = 6
+// MaxShortCodeLength is the width of the short_code column.
+const MaxShortCodeLength = 64
+
+// DefaultRedirectCode is the HTTP status used by links that did not choose one (302 Found).
+const DefaultRedirectCode = 302
 
 type ShortLink struct {
-	ID          uuid.UUID
-	ShortCode   string
-	OriginalURL string
-	CreatedAt   time.Time
+	ID               uuid.UUID
+	Domain           string
+	ShortCode        string
+	OriginalURL      string
+	RawURL           string
+	CreatedAt        time.Time
+	ExpiresAt        *time.Time
+	FallbackURL      string
+	MaxClicks        *int64
+	ClicksUsed       int64
+	PasswordHash     string
+	DeletedAt        *time.Time
+	OwnerID          *uuid.UUID
+	TotalClicks      int64
+	RedirectCode     int
+	QueryPassthrough QueryPassthrough
+	PathPassthrough  bool
+	Rules            []Rule
+	Variants         []Variant
+	StickyVariants   bool
+	DeepLink         *DeepLink
+	AlwaysPreview    bool
+	Title            string
+	Description      string
+	Tags             []string
+	Metadata         *Metadata
+}
+
+func (l *ShortLink) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+func (l *ShortLink) Exhausted() bool {
+	return l.MaxClicks != nil && l.ClicksUsed >= *l.MaxClicks
+}
+
+func (l *ShortLink) ClickBudget() ClickBudget {
+	budget := ClickBudget{
+		MaxClicks:  l.MaxClicks,
+		ClicksUsed: l.ClicksUsed,
+	}
+
+	if l.MaxClicks != nil {
+		remaining := max(*l.MaxClicks-l.ClicksUsed, 0)
+		budget.Remaining = &remaining
+	}
+
+	return budget
+}
+
+// RedirectStatus returns the link's redirect code, falling back to DefaultRedirectCode for links cached
+// before the code was stored.
+func (l *ShortLink) RedirectStatus() int {
+	if l.RedirectCode == 0 {
+		return DefaultRedirectCode
+	}
+
+	return l.RedirectCode
+}
+
+// PermanentRedirect reports whether browsers may cache the redirect, skipping later visits to the link.
+func (l *ShortLink) PermanentRedirect() bool {
+	status := l.RedirectStatus()
+	return status == 301 || status == 308
+}
+
+// Personalized reports whether the target depends on the visitor, so the redirect must not be cached.
+func (l *ShortLink) Personalized() bool {
+	return len(l.Rules) > 0 || len(l.Variants) > 0
+}
+
+func (l *ShortLink) Protected() bool {
+	return l.PasswordHash != ""
+}
+
+func (l *ShortLink) Deleted() bool {
+	return l.DeletedAt != nil
+}
+
+func (l *ShortLink) OwnedBy(ownerID *uuid.UUID) bool {
+	return l.OwnerID != nil && ownerID != nil && *l.OwnerID == *ownerID
+}
+
+func (l *ShortLink) ReadableBy(ownerID *uuid.UUID) bool {
+	return l.OwnerID == nil || l.OwnedBy(ownerID)
+}
+
+type CreateParams struct {
+	Domain           string
+	ShortCode        string
+	OriginalURL      string
+	RawURL           string
+	ExpiresAt        *time.Time
+	FallbackURL      string
+	MaxClicks        *int64
+	Password         string
+	PasswordHash     string
+	OwnerID          *uuid.UUID
+	TargetHash       string
+	TargetDomain     string
+	Dedupe           bool
+	RedirectCode     int
+	QueryPassthrough QueryPassthrough
+	PathPassthrough  bool
+	Rules            []Rule
+	Variants         []Variant
+	StickyVariants   bool
+	DeepLink         *DeepLink
+	AlwaysPreview    bool
+	Title            string
+	Description      string
+}
+
+type CreateResult struct {
+	Link *ShortLink
+	Err  error
+}
+
+type UpdateParams struct {
+	OriginalURL      *string
+	RawURL           *string
+	TargetHash       *string
+	TargetDomain     *string
+	RedirectCode     *int
+	QueryPassthrough *QueryPassthrough
+	PathPassthrough  *bool
+	Rules            *[]Rule
+	Variants         *[]Variant
+	StickyVariants   *bool
+	// DeepLink replaces the deep link; an empty one removes it.
+	DeepLink      *DeepLink
+	AlwaysPreview *bool
+	Title         *string
+	Description   *string
+}
+
+type ListSort string
+
+const (
+	ListSortCreatedAt   ListSort = "createdAt"
+	ListSortTotalClicks ListSort = "totalClicks"
+)
+
+type ListFilter struct {
+	OwnerID        *uuid.UUID
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	TargetDomain   string
+	TargetContains string
+	Tag            string
+}
+
+// ListCursor points at the last link of the previous page; only the field matching the sort is used.
+type ListCursor struct {
+	CreatedAt   time.Time `json:"createdAt"`
+	TotalClicks int64     `json:"totalClicks"`
+	ID          uuid.UUID `json:"id"`
+}
+
+type ListParams struct {
+	Filter    ListFilter
+	Sort      ListSort
+	Ascending bool
+	Cursor    *ListCursor
+	Limit     int
+}
+
+type ListPage struct {
+	Links      []ShortLink
+	NextCursor *ListCursor
+}
+
+type ClickBudget struct {
+	MaxClicks  *int64 `json:"maxClicks"`
+	ClicksUsed int64  `json:"clicksUsed"`
+	Remaining  *int64 `json:"remaining"`
+}
+
+type UnlockToken struct {
+	Value     string
+	ExpiresAt time.Time
+}
+
+// HandoffReport is what a handoff page echoes back with its outcome: the rule and variant that chose the
+// web URL and the visit source.
+type HandoffReport struct {
+	Rule    string
+	Variant string
+	Source  string
 }
@@ -0,0 +1,88 @@
+package shortlink
+
+import (
+	"net/url"
+	"strings"
+)
+
+// QueryPassthrough controls whether the query string of a redirect request is merged into the target URL
+// and which side wins when both set the same parameter.
+type QueryPassthrough string
+
+const (
+	QueryPassthroughOff           QueryPassthrough = "off"
+	QueryPassthroughPreferTarget  QueryPassthrough = "target"
+	QueryPassthroughPreferRequest QueryPassthrough = "request"
+)
+
+// targetFrom applies the link's passthrough settings to base.
+func (l *ShortLink) targetFrom(base, extraPath, rawQuery string) (string, error) {
+	if extraPath != "" && !l.PathPassthrough {
+		return "", ErrShortLinkNotFound
+	}
+
+	mergeQuery := rawQuery != "" && l.QueryPassthrough != "" && l.QueryPassthrough != QueryPassthroughOff
+	if extraPath == "" && !mergeQuery {
+		return base, nil
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+
+	if extraPath != "" {
+		if err := appendPath(u, extraPath); err != nil {
+			return "", err
+		}
+	}
+
+	if mergeQuery {
+		incoming, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return "", ErrInvalidPassthrough
+		}
+
+		query := u.Query()
+		for key, values := range incoming {
+			if _, ok := query[key]; ok && l.QueryPassthrough == QueryPassthroughPreferTarget {
+				continue
+			}
+			query[key] = values
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String(), nil
+}
+
+// appendPath adds escaped path segments to the target path. Dot segments are rejected so a suffix
+// can never climb above the target path.
+func appendPath(u *url.URL, extraPath string) error {
+	var decoded, escaped []string
+
+	segments := strings.Split(extraPath, "/")
+	for i, segment := range segments {
+		if segment == "" && i != len(segments)-1 {
+			continue
+		}
+
+		value, err := url.PathUnescape(segment)
+		if err != nil || value == "." || value == ".." {
+			return ErrInvalidPassthrough
+		}
+
+		decoded = append(decoded, value)
+		escaped = append(escaped, url.PathEscape(value))
+	}
+
+	if len(decoded) == 0 {
+		return nil
+	}
+
+	base := strings.TrimSuffix(u.EscapedPath(), "/")
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + strings.Join(decoded, "/")
+	u.RawPath = base + "/" + strings.Join(escaped, "/")
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package shortlink
+
+import (
+	"context"
+	"image/color"
+	"strings"
+)
+
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+// Error correction levels of a QR code; higher levels survive more damage to the image but need more modules.
+const (
+	QRLevelLow      = "L"
+	QRLevelMedium   = "M"
+	QRLevelQuartile = "Q"
+	QRLevelHigh     = "H"
+)
+
+// QRSourceMarker is the query pair appended to short URLs in QR codes. Redirects strip it before routing
+// and record the visit as a QR scan.
+const QRSourceMarker = "src=qr"
+
+// QRCodeOptions describe a QR code image: Size is its width in pixels and Margin the quiet zone in modules.
+type QRCodeOptions struct {
+	Format     string
+	Size       int
+	Level      string
+	Margin     int
+	Foreground color.RGBA
+	Background color.RGBA
+}
+
+// QRCodeEncoder renders content as a QR code image in the format of the options.
+type QRCodeEncoder interface {
+	Encode(content string, options QRCodeOptions) ([]byte, error)
+}
+
+// QRCodeService renders QR codes, reusing images rendered before with the same options.
+type QRCodeService interface {
+	Render(ctx context.Context, content string, options QRCodeOptions) ([]byte, error)
+}
+
+// StripQRMarker removes QRSourceMarker from a raw query and reports whether it was there. Other pairs keep
+// their order and encoding.
+func StripQRMarker(rawQuery string) (string, bool) {
+	if rawQuery == "" {
+		return rawQuery, false
+	}
+
+	pairs := strings.Split(rawQuery, "&")
+	kept := pairs[:0]
+	found := false
+	for _, pair := range pairs {
+		if pair == QRSourceMarker {
+			found = true
+			continue
+		}
+		kept = append(kept, pair)
+	}
+
+	return strings.Join(kept, "&"), found
+}
@@ -0,0 +1,29 @@
+package shortlink_test
+
+import (
+	"testing"
+
+	shortlink "shortener/src/internal/domain/short_link"
+)
+
+func TestStripQRMarker(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+		found bool
+	}{
+		{query: "", want: "", found: false},
+		{query: "src=qr", want: "", found: true},
+		{query: "a=1&src=qr&b=%20x", want: "a=1&b=%20x", found: true},
+		{query: "src=qrcode&src=web", want: "src=qrcode&src=web", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, found := shortlink.StripQRMarker(tt.query)
+			if got != tt.want || found != tt.found {
+				t.Fatalf("got (%q, %v), want (%q, %v)", got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
@@ -1,8 +1,33 @@
 package shortlink
 
-import "context"
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
 
 type ShortLinkRepository interface {
-	Create(ctx context.Context, shortURL, originalURL string) (*ShortLink, error)
-	Get(ctx context.Context, shortURL string) (*ShortLink, error)
+	Create(ctx context.Context, params CreateParams) (*ShortLink, error)
+	// CreateBatch inserts all links at once and reports ErrShortLinkAlreadyExists per item on code collisions.
+	CreateBatch(ctx context.Context, params []CreateParams) ([]CreateResult, error)
+	// Get looks the code up on the given domain; "" is the default domain.
+	Get(ctx context.Context, domain, shortURL string) (*ShortLink, error)
+	// AvailableCodes returns the codes that are not taken on the domain, in the order they were given.
+	AvailableCodes(ctx context.Context, domain string, codes []string) ([]string, error)
+	// List returns up to params.Limit live links matching the filter, starting after params.Cursor.
+	List(ctx context.Context, params ListParams) (*ListPage, error)
+	GetDeduplicated(ctx context.Context, ownerID *uuid.UUID, domain, targetHash string) (*ShortLink, error)
+	// RetireDeduplicated takes the link out of deduplication, so a new link for its target can take its place.
+	RetireDeduplicated(ctx context.Context, id uuid.UUID) error
+	ConsumeClick(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, id uuid.UUID, params UpdateParams) (*ShortLink, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// AttachTags adds the owner's tags to the link, creating tags the owner has not used yet.
+	AttachTags(ctx context.Context, linkID, ownerID uuid.UUID, tags []string) error
+	// DetachTag returns ErrTagNotFound if the tag is not attached to the link.
+	DetachTag(ctx context.Context, linkID, ownerID uuid.UUID, tag string) error
+	// IDsByTag returns the live links with the tag; a nil owner matches the tag of every owner.
+	IDsByTag(ctx context.Context, ownerID *uuid.UUID, tag string) ([]uuid.UUID, error)
+	// SetMetadata stores the metadata unless the link is deleted or its target is no longer targetURL.
+	SetMetadata(ctx context.Context, id uuid.UUID, targetURL string, metadata Metadata) error
 }
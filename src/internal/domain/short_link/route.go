@@ -0,0 +1,32 @@
+package shortlink
+
+// Destination is the target of a redirect and what chose it: a rule, a split variant, or neither for
+// OriginalURL.
+type Destination struct {
+	URL     string
+	Rule    *Rule
+	Variant *Variant
+}
+
+// Route resolves the redirect for the visitor. Rules are checked first; visitors no rule matches are split
+// across the variants, if any. extraPath is the still escaped part of the request path after the short code
+// and rawQuery its query string; both are used only if the link enables passthrough, and a suffix on a link
+// without path passthrough gives ErrShortLinkNotFound.
+func (l *ShortLink) Route(visitor Visitor, extraPath, rawQuery string) (Destination, error) {
+	var dest Destination
+	base := l.OriginalURL
+
+	if dest.Rule = l.MatchRule(visitor); dest.Rule != nil {
+		base = dest.Rule.TargetURL
+	} else if dest.Variant = l.PickVariant(visitor); dest.Variant != nil {
+		base = dest.Variant.TargetURL
+	}
+
+	target, err := l.targetFrom(base, extraPath, rawQuery)
+	if err != nil {
+		return Destination{}, err
+	}
+	dest.URL = target
+
+	return dest, nil
+}
@@ -0,0 +1,162 @@
+package shortlink_test
+
+import (
+	"errors"
+	"testing"
+
+	shortlink "shortener/src/internal/domain/short_link"
+)
+
+func TestShortLink_Route(t *testing.T) {
+	link := shortlink.ShortLink{
+		OriginalURL:      "https://example.com/",
+		QueryPassthrough: shortlink.QueryPassthroughPreferRequest,
+		Rules: []shortlink.Rule{
+			{Name: "ios", TargetURL: "https://apps.apple.com/app", OS: []string{shortlink.OSiOS}},
+			{Name: "german", TargetURL: "https://example.de/", Languages: []string{"de"}, Countries: []string{"DE", "AT"}},
+			{Name: "mobile", TargetURL: "https://m.example.com/", Devices: []string{shortlink.DeviceMobile}},
+		},
+		Variants: []shortlink.Variant{
+			{Name: "a", TargetURL: "https://example.com/a", Weight: 1},
+			{Name: "b", TargetURL: "https://example.com/b", Weight: 1},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		visitor shortlink.Visitor
+		want    string
+		rule    string
+		variant string
+	}{
+		{
+			name:    "first matching rule wins",
+			visitor: shortlink.Visitor{Device: shortlink.DeviceMobile, OS: shortlink.OSiOS},
+			want:    "https://apps.apple.com/app?ref=x",
+			rule:    "ios",
+		},
+		{
+			name:    "all conditions of a rule must match",
+			visitor: shortlink.Visitor{Language: "de-de", Country: "CH", Device: shortlink.DeviceMobile},
+			want:    "https://m.example.com/?ref=x",
+			rule:    "mobile",
+		},
+		{
+			name:    "language range matches subtags",
+			visitor: shortlink.Visitor{Language: "de-at", Country: "AT"},
+			want:    "https://example.de/?ref=x",
+			rule:    "german",
+		},
+		{
+			name:    "visitors no rule matches are split across variants",
+			visitor: shortlink.Visitor{Device: shortlink.DeviceDesktop, Roll: 0.9},
+			want:    "https://example.com/b?ref=x",
+			variant: "b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest, err := link.Route(tt.visitor, "", "ref=x")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if dest.URL != tt.want {
+				t.Fatalf("got %q, want %q", dest.URL, tt.want)
+			}
+
+			rule, variant := "", ""
+			if dest.Rule != nil {
+				rule = dest.Rule.Name
+			}
+			if dest.Variant != nil {
+				variant = dest.Variant.Name
+			}
+			if rule != tt.rule || variant != tt.variant {
+				t.Fatalf("got rule %q and variant %q, want %q and %q", rule, variant, tt.rule, tt.variant)
+			}
+		})
+	}
+}
+
+func TestShortLink_Route_WithoutRulesOrVariants(t *testing.T) {
+	link := shortlink.ShortLink{OriginalURL: "https://example.com/"}
+
+	dest, err := link.Route(shortlink.Visitor{Roll: 0.5}, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dest.URL != link.OriginalURL || dest.Rule != nil || dest.Variant != nil {
+		t.Fatalf("unexpected destination: %+v", dest)
+	}
+}
+
+func TestShortLink_Route_Passthrough(t *testing.T) {
+	tests := []struct {
+		name  string
+		link  shortlink.ShortLink
+		path  string
+		query string
+		want  string
+		err   error
+	}{
+		{
+			name:  "passthrough disabled drops the query",
+			link:  shortlink.ShortLink{OriginalURL: "https://example.com/a?x=1"},
+			query: "utm_source=y",
+			want:  "https://example.com/a?x=1",
+		},
+		{
+			name:  "target wins on conflicts",
+			link:  shortlink.ShortLink{OriginalURL: "https://example.com/a?x=1", QueryPassthrough: "target"},
+			query: "x=2&utm_source=y",
+			want:  "https://example.com/a?utm_source=y&x=1",
+		},
+		{
+			name:  "request wins on conflicts",
+			link:  shortlink.ShortLink{OriginalURL: "https://example.com/a?x=1", QueryPassthrough: "request"},
+			query: "x=2&x=3",
+			want:  "https://example.com/a?x=2&x=3",
+		},
+		{
+			name: "suffix is appended to the target path",
+			link: shortlink.ShortLink{OriginalURL: "https://example.com/docs/?v=1#top", PathPassthrough: true},
+			path: "guide/intro%20page/",
+			want: "https://example.com/docs/guide/intro%20page/?v=1#top",
+		},
+		{
+			name: "encoded slash stays inside a segment",
+			link: shortlink.ShortLink{OriginalURL: "https://example.com", PathPassthrough: true},
+			path: "a%2Fb",
+			want: "https://example.com/a%2Fb",
+		},
+		{
+			name: "suffix without path passthrough",
+			link: shortlink.ShortLink{OriginalURL: "https://example.com"},
+			path: "x",
+			err:  shortlink.ErrShortLinkNotFound,
+		},
+		{
+			name: "dot segments are rejected",
+			link: shortlink.ShortLink{OriginalURL: "https://example.com/base/", PathPassthrough: true},
+			path: "a/%2e%2e/secret",
+			err:  shortlink.ErrInvalidPassthrough,
+		},
+	}
+
+	for _, tt := range tests {
+		dest, err := tt.link.Route(shortlink.Visitor{}, tt.path, tt.query)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%s: expected %v, got %q, %v", tt.name, tt.err, dest.URL, err)
+			}
+			continue
+		}
+
+		if err != nil || dest.URL != tt.want {
+			t.Errorf("%s: expected %q, got %q, %v", tt.name, tt.want, dest.URL, err)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package shortlink
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxRules bounds the rules of a link; they are evaluated in order on every redirect.
+const MaxRules = 20
+
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+)
+
+const (
+	OSiOS     = "ios"
+	OSAndroid = "android"
+	OSWindows = "windows"
+	OSMacOS   = "macos"
+	OSLinux   = "linux"
+)
+
+var knownDevices = []string{DeviceMobile, DeviceTablet, DeviceDesktop, DeviceBot}
+var knownOS = []string{OSiOS, OSAndroid, OSWindows, OSMacOS, OSLinux}
+
+// Rule sends visitors matching all of its conditions to TargetURL. A condition left empty matches anyone;
+// a list condition matches if any of its values does.
+type Rule struct {
+	Name      string    `json:"name"`
+	TargetURL string    `json:"targetURL"`
+	Devices   []string  `json:"devices,omitempty"`
+	OS        []string  `json:"os,omitempty"`
+	Languages []string  `json:"languages,omitempty"`
+	Countries []string  `json:"countries,omitempty"`
+	Schedule  *Schedule `json:"schedule,omitempty"`
+}
+
+// Schedule limits a rule to an absolute period and to daily hours on some weekdays. From and To are "15:04"
+// clock times in Timezone (UTC by default); From after To spans midnight.
+type Schedule struct {
+	Start    *time.Time     `json:"start,omitempty"`
+	End      *time.Time     `json:"end,omitempty"`
+	Weekdays []time.Weekday `json:"weekdays,omitempty"`
+	From     string         `json:"from,omitempty"`
+	To       string         `json:"to,omitempty"`
+	Timezone string         `json:"timezone,omitempty"`
+}
+
+// MatchRule returns the first rule matching the visitor, or nil if the link falls back to OriginalURL.
+func (l *ShortLink) MatchRule(visitor Visitor) *Rule {
+	for i := range l.Rules {
+		if l.Rules[i].Matches(visitor) {
+			return &l.Rules[i]
+		}
+	}
+
+	return nil
+}
+
+func (r *Rule) Matches(visitor Visitor) bool {
+	if len(r.Devices) > 0 && !slices.Contains(r.Devices, visitor.Device) {
+		return false
+	}
+
+	if len(r.OS) > 0 && !slices.Contains(r.OS, visitor.OS) {
+		return false
+	}
+
+	if len(r.Countries) > 0 && !slices.Contains(r.Countries, visitor.Country) {
+		return false
+	}
+
+	if len(r.Languages) > 0 && !slices.ContainsFunc(r.Languages, func(language string) bool {
+		return languageMatches(language, visitor.Language)
+	}) {
+		return false
+	}
+
+	return r.Schedule == nil || r.Schedule.Matches(visitor.Time)
+}
+
+func (s *Schedule) Matches(now time.Time) bool {
+	if s.Start != nil && now.Before(*s.Start) {
+		return false
+	}
+
+	if s.End != nil && !now.Before(*s.End) {
+		return false
+	}
+
+	location, err := loadLocation(s.Timezone)
+	if err != nil {
+		return false
+	}
+	local := now.In(location)
+
+	if len(s.Weekdays) > 0 && !slices.Contains(s.Weekdays, local.Weekday()) {
+		return false
+	}
+
+	if s.From == "" && s.To == "" {
+		return true
+	}
+
+	from, fromErr := clockMinutes(s.From, 0)
+	to, toErr := clockMinutes(s.To, 24*60)
+	if fromErr != nil || toErr != nil {
+		return false
+	}
+
+	minute := local.Hour()*60 + local.Minute()
+	if from <= to {
+		return minute >= from && minute < to
+	}
+
+	return minute >= from || minute < to
+}
+
+// NormalizeRules checks the rules and lower-cases devices, systems and languages and upper-cases countries
+// so they compare directly against a Visitor. Errors wrap ErrInvalidRule.
+func NormalizeRules(rules []Rule) ([]Rule, error) {
+	if len(rules) > MaxRules {
+		return nil, fmt.Errorf("%w: at most %d rules are allowed", ErrInvalidRule, MaxRules)
+	}
+
+	normalized := make([]Rule, len(rules))
+	names := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		rule.Name = strings.TrimSpace(rule.Name)
+		if rule.Name == "" {
+			return nil, fmt.Errorf("%w: rule %d has no name", ErrInvalidRule, i)
+		}
+
+		if names[rule.Name] {
+			return nil, fmt.Errorf("%w: duplicate rule name %q", ErrInvalidRule, rule.Name)
+		}
+		names[rule.Name] = true
+
+		if rule.TargetURL == "" {
+			return nil, fmt.Errorf("%w: rule %q has no target URL", ErrInvalidRule, rule.Name)
+		}
+
+		rule.Devices = lowerAll(rule.Devices)
+		for _, device := range rule.Devices {
+			if !slices.Contains(knownDevices, device) {
+				return nil, fmt.Errorf("%w: rule %q: unknown device %q", ErrInvalidRule, rule.Name, device)
+			}
+		}
+
+		rule.OS = lowerAll(rule.OS)
+		for _, os := range rule.OS {
+			if !slices.Contains(knownOS, os) {
+				return nil, fmt.Errorf("%w: rule %q: unknown OS %q", ErrInvalidRule, rule.Name, os)
+			}
+		}
+
+		rule.Languages = lowerAll(rule.Languages)
+		rule.Countries = upperAll(rule.Countries)
+		for _, country := range rule.Countries {
+			if len(country) != 2 {
+				return nil, fmt.Errorf("%w: rule %q: invalid country %q", ErrInvalidRule, rule.Name, country)
+			}
+		}
+
+		if rule.Schedule != nil {
+			if err := rule.Schedule.validate(); err != nil {
+				return nil, fmt.Errorf("%w: rule %q: %w", ErrInvalidRule, rule.Name, err)
+			}
+		}
+
+		normalized[i] = rule
+	}
+
+	return normalized, nil
+}
+
+func (s *Schedule) validate() error {
+	if s.Start != nil && s.End != nil && !s.Start.Before(*s.End) {
+		return errors.New("schedule start must be before its end")
+	}
+
+	if _, err := loadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+
+	for _, day := range s.Weekdays {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("invalid weekday %d", day)
+		}
+	}
+
+	if _, err := clockMinutes(s.From, 0); err != nil {
+		return fmt.Errorf("invalid from time %q", s.From)
+	}
+
+	if _, err := clockMinutes(s.To, 0); err != nil {
+		return fmt.Errorf("invalid to time %q", s.To)
+	}
+
+	return nil
+}
+
+// languageMatches compares language ranges by prefix, so "en" matches "en-us" but "en-gb" does not.
+func languageMatches(ruleLanguage, language string) bool {
+	return language == ruleLanguage || strings.HasPrefix(language, ruleLanguage+"-")
+}
+
+func clockMinutes(value string, empty int) (int, error) {
+	if value == "" {
+		return empty, nil
+	}
+
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+var locations sync.Map
+
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	if location, ok := locations.Load(name); ok {
+		return location.(*time.Location), nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, location)
+
+	return location, nil
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(strings.TrimSpace(value))
+	}
+
+	return lowered
+}
+
+func upperAll(values []string) []string {
+	uppered := make([]string, len(values))
+	for i, value := range values {
+		uppered[i] = strings.ToUpper(strings.TrimSpace(value))
+	}
+
+	return uppered
+}
@@ -0,0 +1,106 @@
+package shortlink_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	shortlink "shortener/src/internal/domain/short_link"
+)
+
+func TestSchedule_Matches(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	end := at("2026-03-01T00:00:00Z")
+
+	tests := []struct {
+		name     string
+		schedule shortlink.Schedule
+		now      time.Time
+		want     bool
+	}{
+		{
+			name:     "inside daily hours in the timezone",
+			schedule: shortlink.Schedule{From: "09:00", To: "18:00", Timezone: "Europe/Moscow"},
+			now:      at("2026-02-02T07:30:00Z"),
+			want:     true,
+		},
+		{
+			name:     "outside daily hours in the timezone",
+			schedule: shortlink.Schedule{From: "09:00", To: "18:00", Timezone: "Europe/Moscow"},
+			now:      at("2026-02-02T15:00:00Z"),
+			want:     false,
+		},
+		{
+			name:     "window spanning midnight",
+			schedule: shortlink.Schedule{From: "22:00", To: "06:00"},
+			now:      at("2026-02-02T01:00:00Z"),
+			want:     true,
+		},
+		{
+			name:     "weekday is taken in the timezone",
+			schedule: shortlink.Schedule{Weekdays: []time.Weekday{time.Saturday}, Timezone: "Asia/Tokyo"},
+			now:      at("2026-02-06T20:00:00Z"),
+			want:     true,
+		},
+		{
+			name:     "end is exclusive",
+			schedule: shortlink.Schedule{End: &end},
+			now:      end,
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Matches(tt.now); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeRules(t *testing.T) {
+	rules, err := shortlink.NormalizeRules([]shortlink.Rule{{
+		Name:      " eu ",
+		TargetURL: "https://example.eu",
+		Devices:   []string{"Mobile"},
+		Languages: []string{"PT-BR"},
+		Countries: []string{"de"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rule := rules[0]
+	if rule.Name != "eu" || rule.Devices[0] != "mobile" || rule.Languages[0] != "pt-br" || rule.Countries[0] != "DE" {
+		t.Fatalf("unexpected rule: %+v", rule)
+	}
+
+	invalid := map[string][]shortlink.Rule{
+		"duplicate name": {
+			{Name: "a", TargetURL: "https://a.example"},
+			{Name: "a", TargetURL: "https://b.example"},
+		},
+		"unknown device": {{Name: "a", TargetURL: "https://a.example", Devices: []string{"watch"}}},
+		"unknown timezone": {{
+			Name:      "a",
+			TargetURL: "https://a.example",
+			Schedule:  &shortlink.Schedule{Timezone: "Mars/Olympus"},
+		}},
+		"invalid clock time": {{Name: "a", TargetURL: "https://a.example", Schedule: &shortlink.Schedule{From: "25:00"}}},
+	}
+
+	for name, rules := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := shortlink.NormalizeRules(rules); !errors.Is(err, shortlink.ErrInvalidRule) {
+				t.Fatalf("expected ErrInvalidRule, got %v", err)
+			}
+		})
+	}
+}
@@ -1,8 +1,43 @@
 package shortlink
 
-import "context"
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
 
 type ShortLinkService interface {
-	Create(ctx context.Context, shortURL, originalURL string) (*ShortLink, error)
-	Get(ctx context.Context, shortURL string) (*ShortLink, error)
+	Create(ctx context.Context, params CreateParams) (*ShortLink, error)
+	// GetOrCreate returns the owner's existing deduplicated link for the same target, or creates one.
+	// The boolean result reports whether a new link was created.
+	GetOrCreate(ctx context.Context, params CreateParams) (*ShortLink, bool, error)
+	CreateBatch(ctx context.Context, params []CreateParams) ([]CreateResult, error)
+	// SuggestAliases returns free codes on the domain derived from a taken alias, most similar first.
+	SuggestAliases(ctx context.Context, domain, alias string) ([]string, error)
+	// Get returns ErrShortLinkExpired together with the link once it has expired,
+	// so callers can still use its fallback URL.
+	Get(ctx context.Context, domain, shortURL string) (*ShortLink, error)
+	ConsumeClick(ctx context.Context, link *ShortLink) error
+	// Owned returns the link if the owner may read it: either the link has no owner or it belongs to ownerID.
+	Owned(ctx context.Context, ownerID *uuid.UUID, domain, shortURL string) (*ShortLink, error)
+	List(ctx context.Context, params ListParams) (*ListPage, error)
+	Update(ctx context.Context, ownerID *uuid.UUID, domain, shortURL string, params UpdateParams) (*ShortLink, error)
+	Delete(ctx context.Context, ownerID *uuid.UUID, domain, shortURL string) error
+	AttachTags(ctx context.Context, ownerID *uuid.UUID, domain, shortURL string, tags []string) (*ShortLink, error)
+	DetachTag(ctx context.Context, ownerID *uuid.UUID, domain, shortURL, tag string) error
+	// LinkIDsByTag returns the owner's live links with the tag, or those of every owner for a nil owner.
+	LinkIDsByTag(ctx context.Context, ownerID *uuid.UUID, tag string) ([]uuid.UUID, error)
+}
+
+type UnlockService interface {
+	Unlock(ctx context.Context, link *ShortLink, password, clientIP string) (*UnlockToken, error)
+	Verify(link *ShortLink, token string) bool
+}
+
+// HandoffService lets only a rendered handoff page report its outcome, once.
+type HandoffService interface {
+	// Issue returns the token the handoff page sends back with its report.
+	Issue(link *ShortLink, report HandoffReport) (string, error)
+	// Redeem reports whether the token was issued for the link and report, is not expired and was not redeemed.
+	Redeem(ctx context.Context, link *ShortLink, report HandoffReport, token string) bool
 }
@@ -0,0 +1,23 @@
+package shortlink
+
+// IsShortCodeChar reports whether ch may appear in a short code: ASCII letters, digits, '-' and '_'.
+func IsShortCodeChar(ch rune) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '-' || ch == '_'
+}
+
+// ValidAlias reports whether a custom alias uses only short code characters
+// and neither starts nor ends with a separator.
+func ValidAlias(alias string) bool {
+	if alias == "" {
+		return false
+	}
+
+	for _, ch := range alias {
+		if !IsShortCodeChar(ch) {
+			return false
+		}
+	}
+
+	first, last := alias[0], alias[len(alias)-1]
+	return first != '-' && first != '_' && last != '-' && last != '_'
+}
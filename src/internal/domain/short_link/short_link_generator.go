@@ -1,5 +1,9 @@
 package shortlink
 
+import "context"
+
 type ShortLinkGenerator interface {
-	Generate() (string, error)
+	// Generate returns a candidate code for the target URL. attempt starts at 0 and grows
+	// with every collision, so deterministic generators can step to their next fallback code.
+	Generate(ctx context.Context, originalURL string, attempt int) (string, error)
 }
@@ -0,0 +1,50 @@
+package shortlink
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxTags bounds the tags of a link.
+	MaxTags      = 50
+	MaxTagLength = 64
+)
+
+// NormalizeTags trims and lower-cases tags and drops duplicates, keeping the first occurrence. Tags must be
+// non-empty and free of '/', since they appear in URL paths. Errors wrap ErrInvalidTag.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	if len(normalized) > MaxTags {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidTag, MaxTags)
+	}
+
+	return normalized, nil
+}
+
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	switch {
+	case tag == "":
+		return "", fmt.Errorf("%w: tag is empty", ErrInvalidTag)
+	case utf8.RuneCountInString(tag) > MaxTagLength:
+		return "", fmt.Errorf("%w: tag %q is longer than %d characters", ErrInvalidTag, tag, MaxTagLength)
+	case strings.Contains(tag, "/"):
+		return "", fmt.Errorf("%w: tag %q contains '/'", ErrInvalidTag, tag)
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,46 @@
+package shortlink_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	shortlink "shortener/src/internal/domain/short_link"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := shortlink.NormalizeTags([]string{" Spring-Sale ", "newsletter", "spring-sale"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"spring-sale", "newsletter"}; !slices.Equal(tags, want) {
+		t.Fatalf("got %v, want %v", tags, want)
+	}
+}
+
+func TestNormalizeTags_Invalid(t *testing.T) {
+	tooMany := make([]string, shortlink.MaxTags+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("t", i+1)
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+	}{
+		{name: "empty", tags: []string{"  "}},
+		{name: "too long", tags: []string{strings.Repeat("я", shortlink.MaxTagLength+1)}},
+		{name: "slash", tags: []string{"a/b"}},
+		{name: "too many", tags: tooMany},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := shortlink.NormalizeTags(tt.tags); !errors.Is(err, shortlink.ErrInvalidTag) {
+				t.Fatalf("expected ErrInvalidTag, got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package shortlink
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// trackingParams are query parameters that only identify the click source and never change the target page.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "gbraid": true, "wbraid": true, "msclkid": true,
+	"yclid": true, "igshid": true, "mc_cid": true, "mc_eid": true, "_hsenc": true, "_hsmi": true,
+}
+
+// CanonicalURL rewrites a target URL into the stable form used for storage, deduplication and domain rules:
+// lowercase scheme and host, punycode host, no default port, no dot-segments, an explicit root path and
+// query parameters sorted by name. With stripTracking utm_* and click identifiers such as fbclid are removed.
+// A URL that does not parse is returned unchanged.
+func CanonicalURL(rawURL string, stripTracking bool) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	if u.Host != "" {
+		host, port := CanonicalHost(u.Hostname()), u.Port()
+		if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+			port = ""
+		}
+
+		if port != "" {
+			u.Host = net.JoinHostPort(host, port)
+		} else if strings.Contains(host, ":") {
+			u.Host = "[" + host + "]"
+		} else {
+			u.Host = host
+		}
+	}
+
+	if u.Host != "" {
+		escaped := removeDotSegments(u.EscapedPath())
+		if path, err := url.PathUnescape(escaped); err == nil {
+			u.Path, u.RawPath = path, escaped
+		}
+	}
+
+	u.RawQuery = canonicalQuery(u.RawQuery, stripTracking)
+
+	return u.String()
+}
+
+// CanonicalHost lowercases a hostname, drops a trailing dot and converts an internationalized name to punycode.
+func CanonicalHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return host
+	}
+
+	return ascii
+}
+
+func canonicalQuery(rawQuery string, stripTracking bool) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	pairs := strings.Split(rawQuery, "&")
+	kept := pairs[:0]
+	for _, pair := range pairs {
+		if pair == "" {
+			continue
+		}
+
+		if stripTracking {
+			name, _, _ := strings.Cut(pair, "=")
+			if decoded, err := url.QueryUnescape(name); err == nil {
+				name = strings.ToLower(decoded)
+			}
+
+			if trackingParams[name] || strings.HasPrefix(name, "utm_") {
+				continue
+			}
+		}
+
+		kept = append(kept, pair)
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		nameI, _, _ := strings.Cut(kept[i], "=")
+		nameJ, _, _ := strings.Cut(kept[j], "=")
+		return nameI < nameJ
+	})
+
+	return strings.Join(kept, "&")
+}
+
+// removeDotSegments resolves "." and ".." path segments as described in RFC 3986, section 5.2.4.
+func removeDotSegments(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(path, "/")
+	out := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		last := i == len(segments)-1
+
+		switch segment {
+		case ".":
+			if last {
+				out = append(out, "")
+			}
+		case "..":
+			if len(out) > 1 {
+				out = out[:len(out)-1]
+			}
+			if last {
+				out = append(out, "")
+			}
+		default:
+			out = append(out, segment)
+		}
+	}
+
+	resolved := strings.Join(out, "/")
+	if !strings.HasPrefix(resolved, "/") {
+		resolved = "/" + resolved
+	}
+
+	return resolved
+}
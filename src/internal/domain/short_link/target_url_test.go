@@ -0,0 +1,53 @@
+package shortlink_test
+
+import (
+	"testing"
+
+	shortlink "shortener/src/internal/domain/short_link"
+)
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		raw           string
+		stripTracking bool
+		want          string
+	}{
+		{raw: "HTTPS://Example.COM", want: "https://example.com/"},
+		{raw: "http://example.com:80/a", want: "http://example.com/a"},
+		{raw: "https://example.com:443/a", want: "https://example.com/a"},
+		{raw: "https://example.com:8443/a", want: "https://example.com:8443/a"},
+		{raw: "https://example.com./a", want: "https://example.com/a"},
+		{raw: "https://example.com/a/./b/../c", want: "https://example.com/a/c"},
+		{raw: "https://example.com/a/b/..", want: "https://example.com/a/"},
+		{raw: "https://example.com/../a", want: "https://example.com/a"},
+		{raw: "https://example.com/a%2Fb", want: "https://example.com/a%2Fb"},
+		{raw: "https://bücher.example/", want: "https://xn--bcher-kva.example/"},
+		{raw: "https://example.com/?b=2&a=1&b=1", want: "https://example.com/?a=1&b=2&b=1"},
+		{
+			raw:  "https://example.com/?q=a+b&utm_source=x&fbclid=y",
+			want: "https://example.com/?fbclid=y&q=a+b&utm_source=x",
+		},
+		{
+			raw:           "https://example.com/?q=a+b&utm_source=x&fbclid=y",
+			stripTracking: true,
+			want:          "https://example.com/?q=a+b",
+		},
+		{raw: "https://example.com/?GCLID=1#top", stripTracking: true, want: "https://example.com/#top"},
+		{raw: "https://[::1]:443/", want: "https://[::1]/"},
+	}
+
+	for _, tt := range tests {
+		if got := shortlink.CanonicalURL(tt.raw, tt.stripTracking); got != tt.want {
+			t.Errorf("CanonicalURL(%q, %v) = %q, want %q", tt.raw, tt.stripTracking, got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalURL_Idempotent(t *testing.T) {
+	raw := "HTTP://Bücher.Example:80/x/../y?z=1&a=2&utm_medium=m"
+
+	once := shortlink.CanonicalURL(raw, true)
+	if twice := shortlink.CanonicalURL(once, true); twice != once {
+		t.Fatalf("expected canonical form to be stable, got %q then %q", once, twice)
+	}
+}
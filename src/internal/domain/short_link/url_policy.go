@@ -0,0 +1,9 @@
+package shortlink
+
+import "context"
+
+// URLPolicy decides whether a target URL may be shortened and redirected to.
+type URLPolicy interface {
+	// Check returns an error wrapping ErrTargetNotAllowed if the URL must not be used as a target.
+	Check(ctx context.Context, rawURL string) error
+}
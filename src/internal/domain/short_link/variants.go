@@ -0,0 +1,92 @@
+package shortlink
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxVariants bounds the destinations of an A/B split.
+const MaxVariants = 10
+
+// Variant is a destination of an A/B split. Visits are spread across variants in proportion to their weights;
+// a zero weight pauses a variant.
+type Variant struct {
+	Name      string `json:"name"`
+	TargetURL string `json:"targetURL"`
+	Weight    int    `json:"weight"`
+}
+
+// PickVariant chooses the variant for a visitor that no rule matched, or returns nil if the link has no
+// active variants. With sticky variants the visitor keeps a variant remembered from an earlier visit.
+func (l *ShortLink) PickVariant(visitor Visitor) *Variant {
+	total := 0
+	for _, variant := range l.Variants {
+		total += variant.Weight
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	if l.StickyVariants && visitor.Variant != "" {
+		for i := range l.Variants {
+			if l.Variants[i].Name == visitor.Variant && l.Variants[i].Weight > 0 {
+				return &l.Variants[i]
+			}
+		}
+	}
+
+	point := min(int(visitor.Roll*float64(total)), total-1)
+	for i := range l.Variants {
+		point -= l.Variants[i].Weight
+		if point < 0 {
+			return &l.Variants[i]
+		}
+	}
+
+	return nil
+}
+
+// NormalizeVariants checks the variants: names must be unique, weights non-negative and at least one
+// variant active. Errors wrap ErrInvalidVariant.
+func NormalizeVariants(variants []Variant) ([]Variant, error) {
+	if len(variants) == 0 {
+		return variants, nil
+	}
+
+	if len(variants) > MaxVariants {
+		return nil, fmt.Errorf("%w: at most %d variants are allowed", ErrInvalidVariant, MaxVariants)
+	}
+
+	normalized := make([]Variant, len(variants))
+	names := make(map[string]bool, len(variants))
+	total := 0
+	for i, variant := range variants {
+		variant.Name = strings.TrimSpace(variant.Name)
+		if variant.Name == "" {
+			return nil, fmt.Errorf("%w: variant %d has no name", ErrInvalidVariant, i)
+		}
+
+		if names[variant.Name] {
+			return nil, fmt.Errorf("%w: duplicate variant name %q", ErrInvalidVariant, variant.Name)
+		}
+		names[variant.Name] = true
+
+		if variant.TargetURL == "" {
+			return nil, fmt.Errorf("%w: variant %q has no target URL", ErrInvalidVariant, variant.Name)
+		}
+
+		if variant.Weight < 0 {
+			return nil, fmt.Errorf("%w: variant %q has a negative weight", ErrInvalidVariant, variant.Name)
+		}
+		total += variant.Weight
+
+		normalized[i] = variant
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("%w: all variants have zero weight", ErrInvalidVariant)
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,66 @@
+package shortlink_test
+
+import (
+	"errors"
+	"testing"
+
+	shortlink "shortener/src/internal/domain/short_link"
+)
+
+func TestShortLink_PickVariant(t *testing.T) {
+	link := shortlink.ShortLink{
+		StickyVariants: true,
+		Variants: []shortlink.Variant{
+			{Name: "a", Weight: 1},
+			{Name: "paused", Weight: 0},
+			{Name: "b", Weight: 3},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		visitor shortlink.Visitor
+		want    string
+	}{
+		{name: "low roll", visitor: shortlink.Visitor{Roll: 0}, want: "a"},
+		{name: "weights split the range", visitor: shortlink.Visitor{Roll: 0.25}, want: "b"},
+		{name: "top of the range", visitor: shortlink.Visitor{Roll: 0.999}, want: "b"},
+		{name: "sticky variant", visitor: shortlink.Visitor{Roll: 0.9, Variant: "a"}, want: "a"},
+		{name: "paused variant is not kept", visitor: shortlink.Visitor{Roll: 0.9, Variant: "paused"}, want: "b"},
+		{name: "unknown variant is not kept", visitor: shortlink.Visitor{Roll: 0, Variant: "gone"}, want: "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variant := link.PickVariant(tt.visitor)
+			if variant == nil || variant.Name != tt.want {
+				t.Fatalf("got %+v, want %q", variant, tt.want)
+			}
+		})
+	}
+
+	link.StickyVariants = false
+	if variant := link.PickVariant(shortlink.Visitor{Roll: 0.9, Variant: "a"}); variant.Name != "b" {
+		t.Fatalf("non-sticky split kept the cookie variant %q", variant.Name)
+	}
+}
+
+func TestNormalizeVariants(t *testing.T) {
+	invalid := map[string][]shortlink.Variant{
+		"duplicate name": {
+			{Name: "a", TargetURL: "https://a.example", Weight: 1},
+			{Name: "a", TargetURL: "https://b.example", Weight: 1},
+		},
+		"negative weight": {{Name: "a", TargetURL: "https://a.example", Weight: -1}},
+		"all paused":      {{Name: "a", TargetURL: "https://a.example"}, {Name: "b", TargetURL: "https://b.example"}},
+		"missing target":  {{Name: "a", Weight: 1}},
+	}
+
+	for name, variants := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := shortlink.NormalizeVariants(variants); !errors.Is(err, shortlink.ErrInvalidVariant) {
+				t.Fatalf("expected ErrInvalidVariant, got %v", err)
+			}
+		})
+	}
+}
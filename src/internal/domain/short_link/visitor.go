@@ -0,0 +1,106 @@
+package shortlink
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Visitor describes a redirect request in the terms redirect rules are written in. Empty fields are unknown
+// and match no condition on them. Variant is the split variant remembered for the visitor and Roll, uniform
+// in [0, 1), picks a new one.
+type Visitor struct {
+	Device   string
+	OS       string
+	Language string
+	Country  string
+	Time     time.Time
+	Variant  string
+	Roll     float64
+}
+
+// NewVisitor derives the device and OS from the User-Agent and the preferred language from Accept-Language.
+// country is an ISO 3166-1 alpha-2 code resolved by the caller.
+func NewVisitor(userAgent, acceptLanguage, country string, now time.Time) Visitor {
+	ua := strings.ToLower(userAgent)
+
+	return Visitor{
+		Device:   deviceOf(ua),
+		OS:       osOf(ua),
+		Language: preferredLanguage(acceptLanguage),
+		Country:  strings.ToUpper(country),
+		Time:     now,
+	}
+}
+
+var botMarkers = []string{"bot", "crawler", "spider", "slurp", "facebookexternalhit", "curl/", "wget/"}
+
+func deviceOf(ua string) string {
+	switch {
+	case ua == "":
+		return ""
+	case containsAny(ua, botMarkers...):
+		return DeviceBot
+	case containsAny(ua, "ipad", "tablet", "kindle", "silk/"),
+		strings.Contains(ua, "android") && !strings.Contains(ua, "mobile"):
+		return DeviceTablet
+	case containsAny(ua, "mobi", "iphone", "ipod", "android", "windows phone"):
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
+
+func osOf(ua string) string {
+	switch {
+	case containsAny(ua, "iphone", "ipad", "ipod"):
+		return OSiOS
+	case strings.Contains(ua, "android"):
+		return OSAndroid
+	case strings.Contains(ua, "windows"):
+		return OSWindows
+	case containsAny(ua, "macintosh", "mac os x"):
+		return OSMacOS
+	case strings.Contains(ua, "linux"):
+		return OSLinux
+	default:
+		return ""
+	}
+}
+
+// preferredLanguage returns the lower-cased language range with the highest weight, the first one on ties.
+func preferredLanguage(header string) string {
+	best, bestWeight := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		language, params, _ := strings.Cut(part, ";")
+		language = strings.ToLower(strings.TrimSpace(language))
+		if language == "" || language == "*" {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+
+		if weight > bestWeight {
+			best, bestWeight = language, weight
+		}
+	}
+
+	return best
+}
+
+func containsAny(s string, substrings ...string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+
+	return false
+}
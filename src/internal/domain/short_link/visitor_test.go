@@ -0,0 +1,65 @@
+package shortlink_test
+
+import (
+	"testing"
+	"time"
+
+	shortlink "shortener/src/internal/domain/short_link"
+)
+
+func TestNewVisitor(t *testing.T) {
+	tests := []struct {
+		name           string
+		userAgent      string
+		acceptLanguage string
+		device         string
+		os             string
+		language       string
+	}{
+		{
+			name:           "iphone",
+			userAgent:      "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148",
+			acceptLanguage: "ru-RU,ru;q=0.9,en;q=0.8",
+			device:         shortlink.DeviceMobile,
+			os:             shortlink.OSiOS,
+			language:       "ru-ru",
+		},
+		{
+			name:           "android tablet",
+			userAgent:      "Mozilla/5.0 (Linux; Android 14; SM-X710) AppleWebKit/537.36 Chrome/124.0 Safari/537.36",
+			acceptLanguage: "en;q=0.5, de",
+			device:         shortlink.DeviceTablet,
+			os:             shortlink.OSAndroid,
+			language:       "de",
+		},
+		{
+			name:      "mac desktop",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/605.1.15 Version/17.4 Safari/605.1.15",
+			device:    shortlink.DeviceDesktop,
+			os:        shortlink.OSMacOS,
+		},
+		{
+			name:           "crawler",
+			userAgent:      "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			acceptLanguage: "*, fr;q=0",
+			device:         shortlink.DeviceBot,
+		},
+		{
+			name: "unknown client",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visitor := shortlink.NewVisitor(tt.userAgent, tt.acceptLanguage, "de", time.Time{})
+
+			if visitor.Device != tt.device || visitor.OS != tt.os || visitor.Language != tt.language {
+				t.Fatalf("got %+v, want device %q, os %q, language %q", visitor, tt.device, tt.os, tt.language)
+			}
+
+			if visitor.Country != "DE" {
+				t.Fatalf("got country %q, want DE", visitor.Country)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package visit
+
+import "net/http"
+
+// CountryResolver finds the ISO 3166-1 alpha-2 country of a visitor, or "" if it is unknown.
+type CountryResolver interface {
+	Country(ip string, header http.Header) string
+}
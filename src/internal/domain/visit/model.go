@@ -12,13 +12,41 @@ type Visit struct {
 	CreatedAt time.Time
 	UserAgent string
 	IPAddress string
+	Rule      string
+	Variant   string
+	Handoff   string
+	Source    string
 }
 
+// Handoff values record which way a visitor left the deep link handoff page.
+const (
+	HandoffApp = "app"
+	HandoffWeb = "web"
+)
+
+// Source values tell how the visitor reached the link; visits without one are direct clicks.
+const (
+	SourceDirect = "direct"
+	SourceQR     = "qr"
+)
+
 type PeriodCount struct {
 	Period string `json:"period"`
 	Count  int64  `json:"count"`
 }
 
+// VariantCount compares a split variant with the others: Share is its fraction of the split's visits.
+type VariantCount struct {
+	Variant string  `json:"variant"`
+	Count   int64   `json:"count"`
+	Share   float64 `json:"share"`
+}
+
+type SourceCount struct {
+	Source string `json:"source"`
+	Count  int64  `json:"count"`
+}
+
 type UserAgentCount struct {
 	UserAgent string `json:"userAgent"`
 	Count     int64  `json:"count"`
@@ -1,10 +1,17 @@
 package visit
 
-import "context"
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
 
 type VisitRepository interface {
 	CreateBatch(ctx context.Context, visits []Visit)
-	AnalyticsAggregatedByDay(ctx context.Context, shortURL string) ([]PeriodCount, error)
-	AnalyticsAggregatedByMonth(ctx context.Context, shortURL string) ([]PeriodCount, error)
-	AnalyticsAggregatedByUserAgent(ctx context.Context, shortURL string) ([]UserAgentCount, error)
+	AnalyticsAggregatedByDay(ctx context.Context, linkIDs ...uuid.UUID) ([]PeriodCount, error)
+	AnalyticsAggregatedByMonth(ctx context.Context, linkIDs ...uuid.UUID) ([]PeriodCount, error)
+	AnalyticsAggregatedByUserAgent(ctx context.Context, linkIDs ...uuid.UUID) ([]UserAgentCount, error)
+	AnalyticsAggregatedByVariant(ctx context.Context, linkID uuid.UUID) ([]VariantCount, error)
+	AnalyticsAggregatedBySource(ctx context.Context, linkIDs ...uuid.UUID) ([]SourceCount, error)
+	TotalVisits(ctx context.Context, linkID uuid.UUID) (int64, error)
 }
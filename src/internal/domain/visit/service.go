@@ -2,12 +2,18 @@ package visit
 
 import (
 	"context"
+
+	"github.com/google/uuid"
 )
 
 type VisitService interface {
 	CreateBatch(ctx context.Context, visits []Visit)
 	Register(ctx context.Context, visit Visit) error
-	ByDayAnalytics(ctx context.Context, shortURL string) ([]PeriodCount, error)
-	ByMonthAnalytics(ctx context.Context, shortURL string) ([]PeriodCount, error)
-	ByUserAgentAnalytics(ctx context.Context, shortURL string) ([]UserAgentCount, error)
+	// The day, month, user agent and source analytics sum up the visits of all given links.
+	ByDayAnalytics(ctx context.Context, linkIDs ...uuid.UUID) ([]PeriodCount, error)
+	ByMonthAnalytics(ctx context.Context, linkIDs ...uuid.UUID) ([]PeriodCount, error)
+	ByUserAgentAnalytics(ctx context.Context, linkIDs ...uuid.UUID) ([]UserAgentCount, error)
+	ByVariantAnalytics(ctx context.Context, linkID uuid.UUID, variants []string) ([]VariantCount, error)
+	BySourceAnalytics(ctx context.Context, linkIDs ...uuid.UUID) ([]SourceCount, error)
+	TotalVisits(ctx context.Context, linkID uuid.UUID) (int64, error)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	goredis "github.com/go-redis/redis/v8"
 	"github.com/wb-go/wbf/redis"
 	"github.com/wb-go/wbf/retry"
 )
@@ -28,3 +29,25 @@ func (r *Redis) Get(ctx context.Context, key string) (string, error) {
 	value, err := r.client.GetWithRetry(ctx, r.retry, key)
 	return value, err
 }
+
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	return r.client.DelWithRetry(ctx, r.retry, key)
+}
+
+func (r *Redis) Decrement(ctx context.Context, key string) error {
+	return r.client.Decr(ctx, key).Err()
+}
+
+func (r *Redis) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	var incr *goredis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, expiration)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
@@ -0,0 +1,85 @@
+package codepolicy
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	shortlink "shortener/src/internal/domain/short_link"
+	"strings"
+)
+
+//go:embed profanity.txt
+var profanityList string
+
+// leetReplacer maps digits commonly used in place of letters back to those letters.
+// '1' is ambiguous between 'i' and 'l', so it is handled separately.
+var leetReplacer = strings.NewReplacer(
+	"0", "o",
+	"3", "e",
+	"4", "a",
+	"5", "s",
+	"6", "g",
+	"7", "t",
+	"8", "b",
+	"9", "g",
+	"-", "",
+	"_", "",
+)
+
+// WordListPolicy rejects codes equal to a reserved word and codes containing a word from the
+// embedded profanity list, including leetspeak spellings such as "sh1t" or "f-u-c-k".
+type WordListPolicy struct {
+	reserved  map[string]struct{}
+	profanity []string
+}
+
+func NewWordListPolicy(reserved []string) *WordListPolicy {
+	policy := &WordListPolicy{
+		reserved:  make(map[string]struct{}, len(reserved)),
+		profanity: parseWordList(profanityList),
+	}
+
+	for _, word := range reserved {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" {
+			policy.reserved[word] = struct{}{}
+		}
+	}
+
+	return policy
+}
+
+func (p *WordListPolicy) Check(code string) error {
+	lower := strings.ToLower(code)
+	if _, ok := p.reserved[lower]; ok {
+		return fmt.Errorf("%w: %q is reserved", shortlink.ErrShortCodeNotAllowed, code)
+	}
+
+	folded := leetReplacer.Replace(lower)
+	variants := []string{strings.ReplaceAll(folded, "1", "i"), strings.ReplaceAll(folded, "1", "l")}
+
+	for _, word := range p.profanity {
+		for _, variant := range variants {
+			if strings.Contains(variant, word) {
+				return fmt.Errorf("%w: %q contains a blocked word", shortlink.ErrShortCodeNotAllowed, code)
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseWordList(list string) []string {
+	var words []string
+
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	return words
+}
@@ -0,0 +1,40 @@
+package codepolicy_test
+
+import (
+	"errors"
+	"testing"
+
+	shortlink "shortener/src/internal/domain/short_link"
+	codepolicy "shortener/src/internal/infrastructure/code_policy"
+)
+
+func TestWordListPolicy_Check(t *testing.T) {
+	policy := codepolicy.NewWordListPolicy([]string{"swagger", " Admin "})
+
+	tests := []struct {
+		code    string
+		allowed bool
+	}{
+		{code: "aB3xYz", allowed: true},
+		{code: "my-promo", allowed: true},
+		{code: "swaggers", allowed: true},
+		{code: "swagger", allowed: false},
+		{code: "ADMIN", allowed: false},
+		{code: "xshitx", allowed: false},
+		{code: "Sh1T", allowed: false},
+		{code: "f-u-c-k", allowed: false},
+		{code: "b1tch", allowed: false},
+		{code: "5lut", allowed: false},
+		{code: "wh0re", allowed: false},
+	}
+
+	for _, tt := range tests {
+		err := policy.Check(tt.code)
+		if tt.allowed && err != nil {
+			t.Errorf("expected %q to be allowed, got %v", tt.code, err)
+		}
+		if !tt.allowed && !errors.Is(err, shortlink.ErrShortCodeNotAllowed) {
+			t.Errorf("expected %q to be rejected, got %v", tt.code, err)
+		}
+	}
+}
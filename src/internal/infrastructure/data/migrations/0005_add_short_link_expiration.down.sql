@@ -0,0 +1,3 @@
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS fallback_url,
+    DROP COLUMN IF EXISTS expires_at;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS expires_at   TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS fallback_url TEXT;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS clicks_used,
+    DROP COLUMN IF EXISTS max_clicks;
\ No newline at end of file
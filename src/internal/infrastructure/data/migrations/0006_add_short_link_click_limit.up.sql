@@ -0,0 +1,3 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS max_clicks  BIGINT CHECK (max_clicks > 0),
+    ADD COLUMN IF NOT EXISTS clicks_used BIGINT NOT NULL DEFAULT 0;
\ No newline at end of file
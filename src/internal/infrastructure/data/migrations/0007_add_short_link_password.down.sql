@@ -0,0 +1,2 @@
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS password_hash;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
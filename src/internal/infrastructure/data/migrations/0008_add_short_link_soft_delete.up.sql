@@ -0,0 +1,2 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
\ No newline at end of file
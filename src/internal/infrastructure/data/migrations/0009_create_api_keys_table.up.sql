@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    owner_id    UUID        NOT NULL,
+    name        TEXT        NOT NULL DEFAULT '',
+    prefix      VARCHAR(16) NOT NULL UNIQUE,
+    secret_hash TEXT        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_owner_id
+    ON public.api_keys (owner_id);
\ No newline at end of file
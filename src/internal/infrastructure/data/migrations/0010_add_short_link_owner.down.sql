@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_short_links_owner_id;
+
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS owner_id;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS owner_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_short_links_owner_id
+    ON public.short_links (owner_id);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_short_links_owner_id_target_hash_dedupe;
+
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS dedupe,
+    DROP COLUMN IF EXISTS target_hash;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS target_hash TEXT,
+    ADD COLUMN IF NOT EXISTS dedupe      BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_short_links_owner_id_target_hash_dedupe
+    ON public.short_links (owner_id, target_hash) NULLS NOT DISTINCT
+    WHERE dedupe AND deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_short_links_original_url_trgm;
+DROP INDEX IF EXISTS idx_short_links_target_domain_created_at_id;
+DROP INDEX IF EXISTS idx_short_links_owner_id_total_clicks_id;
+DROP INDEX IF EXISTS idx_short_links_owner_id_created_at_id;
+DROP INDEX IF EXISTS idx_short_links_total_clicks_id;
+DROP INDEX IF EXISTS idx_short_links_created_at_id;
+
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS target_domain,
+    DROP COLUMN IF EXISTS total_clicks;
\ No newline at end of file
@@ -0,0 +1,31 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS total_clicks  BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS target_domain TEXT;
+
+UPDATE short_links sl
+SET total_clicks = v.count
+FROM (SELECT link_id, count(*) AS count FROM visits GROUP BY link_id) v
+WHERE v.link_id = sl.id;
+
+UPDATE short_links
+SET target_domain = lower(substring(original_url FROM '^[A-Za-z][A-Za-z0-9+.-]*://(?:[^@/?#]*@)?([^:/?#]+)'));
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_short_links_created_at_id
+    ON public.short_links (created_at, id) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_short_links_total_clicks_id
+    ON public.short_links (total_clicks, id) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_short_links_owner_id_created_at_id
+    ON public.short_links (owner_id, created_at, id) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_short_links_owner_id_total_clicks_id
+    ON public.short_links (owner_id, total_clicks, id) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_short_links_target_domain_created_at_id
+    ON public.short_links (target_domain, created_at, id) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_short_links_original_url_trgm
+    ON public.short_links USING gin (original_url gin_trgm_ops);
\ No newline at end of file
@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_short_links_owner_id_domain_target_hash_dedupe;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_short_links_owner_id_target_hash_dedupe
+    ON public.short_links (owner_id, target_hash) NULLS NOT DISTINCT
+    WHERE dedupe AND deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_short_links_domain_short_code;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_short_links_short_code
+    ON public.short_links (short_code);
+
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS domain;
+
+DROP TABLE IF EXISTS domains;
\ No newline at end of file
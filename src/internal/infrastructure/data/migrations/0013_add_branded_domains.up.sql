@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS domains
+(
+    host       TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS domain TEXT REFERENCES domains (host);
+
+ALTER TABLE short_links
+    DROP CONSTRAINT IF EXISTS short_links_short_code_key;
+
+DROP INDEX IF EXISTS idx_short_links_short_code;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_short_links_domain_short_code
+    ON public.short_links (domain, short_code) NULLS NOT DISTINCT;
+
+DROP INDEX IF EXISTS idx_short_links_owner_id_target_hash_dedupe;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_short_links_owner_id_domain_target_hash_dedupe
+    ON public.short_links (owner_id, domain, target_hash) NULLS NOT DISTINCT
+    WHERE dedupe AND deleted_at IS NULL;
\ No newline at end of file
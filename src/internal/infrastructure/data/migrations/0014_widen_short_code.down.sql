@@ -0,0 +1,2 @@
+ALTER TABLE short_links
+    ALTER COLUMN short_code TYPE VARCHAR(6);
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS short_code_counter
+(
+    id    BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    value BIGINT  NOT NULL
+);
+
+INSERT INTO short_code_counter (id, value)
+VALUES (TRUE, 0)
+ON CONFLICT DO NOTHING;
\ No newline at end of file
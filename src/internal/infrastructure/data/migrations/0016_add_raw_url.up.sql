@@ -0,0 +1,9 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS raw_url TEXT;
+
+UPDATE short_links
+SET raw_url = original_url
+WHERE raw_url IS NULL;
+
+ALTER TABLE short_links
+    ALTER COLUMN raw_url SET NOT NULL;
\ No newline at end of file
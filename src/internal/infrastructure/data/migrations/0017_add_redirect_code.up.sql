@@ -0,0 +1,3 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS redirect_code SMALLINT NOT NULL DEFAULT 302
+        CHECK (redirect_code IN (301, 302, 307, 308));
\ No newline at end of file
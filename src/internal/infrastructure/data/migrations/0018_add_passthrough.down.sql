@@ -0,0 +1,3 @@
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS path_passthrough,
+    DROP COLUMN IF EXISTS query_passthrough;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS query_passthrough TEXT NOT NULL DEFAULT 'off'
+        CHECK (query_passthrough IN ('off', 'target', 'request')),
+    ADD COLUMN IF NOT EXISTS path_passthrough BOOLEAN NOT NULL DEFAULT FALSE;
\ No newline at end of file
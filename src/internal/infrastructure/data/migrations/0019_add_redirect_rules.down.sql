@@ -0,0 +1,5 @@
+ALTER TABLE visits
+    DROP COLUMN IF EXISTS rule;
+
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS rules;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS rules JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE visits
+    ADD COLUMN IF NOT EXISTS rule TEXT;
\ No newline at end of file
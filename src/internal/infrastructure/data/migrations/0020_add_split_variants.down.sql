@@ -0,0 +1,6 @@
+ALTER TABLE visits
+    DROP COLUMN IF EXISTS variant;
+
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS sticky_variants,
+    DROP COLUMN IF EXISTS variants;
\ No newline at end of file
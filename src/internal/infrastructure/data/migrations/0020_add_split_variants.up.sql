@@ -0,0 +1,6 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS variants JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS sticky_variants BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE visits
+    ADD COLUMN IF NOT EXISTS variant TEXT;
\ No newline at end of file
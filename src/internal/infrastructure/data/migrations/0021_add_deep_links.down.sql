@@ -0,0 +1,5 @@
+ALTER TABLE visits
+    DROP COLUMN IF EXISTS handoff;
+
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS deep_link;
\ No newline at end of file
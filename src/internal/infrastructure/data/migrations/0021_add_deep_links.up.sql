@@ -0,0 +1,5 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS deep_link JSONB;
+
+ALTER TABLE visits
+    ADD COLUMN IF NOT EXISTS handoff TEXT CHECK (handoff IN ('app', 'web'));
\ No newline at end of file
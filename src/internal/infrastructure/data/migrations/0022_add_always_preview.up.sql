@@ -0,0 +1,2 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS always_preview BOOLEAN NOT NULL DEFAULT FALSE;
\ No newline at end of file
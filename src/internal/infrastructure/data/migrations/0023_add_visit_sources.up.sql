@@ -0,0 +1,2 @@
+ALTER TABLE visits
+    ADD COLUMN IF NOT EXISTS source TEXT;
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS short_link_tags;
+
+DROP TABLE IF EXISTS tags;
+
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS title;
\ No newline at end of file
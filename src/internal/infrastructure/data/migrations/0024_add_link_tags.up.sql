@@ -0,0 +1,22 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS title       TEXT,
+    ADD COLUMN IF NOT EXISTS description TEXT;
+
+CREATE TABLE IF NOT EXISTS tags
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    owner_id   UUID        NOT NULL,
+    name       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (owner_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS short_link_tags
+(
+    link_id UUID NOT NULL REFERENCES short_links (id) ON DELETE CASCADE,
+    tag_id  UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (link_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_short_link_tags_tag_id
+    ON public.short_link_tags (tag_id);
\ No newline at end of file
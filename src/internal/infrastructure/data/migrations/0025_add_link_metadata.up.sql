@@ -0,0 +1,2 @@
+ALTER TABLE short_links
+    ADD COLUMN IF NOT EXISTS metadata JSONB;
\ No newline at end of file
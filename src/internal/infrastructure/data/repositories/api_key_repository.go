@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	apikey "shortener/src/internal/domain/api_key"
+
+	"github.com/google/uuid"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+type APIKeyRepository struct {
+	db    *dbpg.DB
+	retry retry.Strategy
+}
+
+func NewAPIKeyRepository(db *dbpg.DB, retry retry.Strategy) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:    db,
+		retry: retry,
+	}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key apikey.APIKey) error {
+	query := `INSERT INTO api_keys (id, owner_id, name, prefix, secret_hash, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := r.db.ExecWithRetry(ctx, r.retry,
+		query,
+		key.ID,
+		key.OwnerID,
+		key.Name,
+		key.Prefix,
+		key.SecretHash,
+		key.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*apikey.APIKey, error) {
+	query := `SELECT id, owner_id, name, prefix, secret_hash, created_at, revoked_at
+				FROM api_keys WHERE prefix = $1`
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.retry, query, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var key apikey.APIKey
+	err = row.Scan(&key.ID, &key.OwnerID, &key.Name, &key.Prefix, &key.SecretHash, &key.CreatedAt, &key.RevokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apikey.ErrAPIKeyNotFound
+		}
+
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+
+	res, err := r.db.ExecWithRetry(ctx, r.retry, query, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return apikey.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	brandeddomain "shortener/src/internal/domain/branded_domain"
+	"shortener/src/pkg/logger"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+type DomainRepository struct {
+	db    *dbpg.DB
+	retry retry.Strategy
+}
+
+func NewDomainRepository(db *dbpg.DB, retry retry.Strategy) *DomainRepository {
+	return &DomainRepository{
+		db:    db,
+		retry: retry,
+	}
+}
+
+func (r *DomainRepository) Create(ctx context.Context, host string) (*brandeddomain.Domain, error) {
+	domain := &brandeddomain.Domain{
+		Host:      host,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	query := `INSERT INTO domains (host, created_at) VALUES ($1, $2)`
+
+	_, err := r.db.ExecWithRetry(ctx, r.retry, query, domain.Host, domain.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && string(pqErr.Code) == "23505" {
+			return nil, brandeddomain.ErrDomainAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func (r *DomainRepository) Get(ctx context.Context, host string) (*brandeddomain.Domain, error) {
+	query := `SELECT host, created_at FROM domains WHERE host = $1`
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.retry, query, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var domain brandeddomain.Domain
+	if err := row.Scan(&domain.Host, &domain.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, brandeddomain.ErrDomainNotFound
+		}
+
+		return nil, err
+	}
+
+	return &domain, nil
+}
+
+func (r *DomainRepository) List(ctx context.Context) ([]brandeddomain.Domain, error) {
+	query := `SELECT host, created_at FROM domains ORDER BY host`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retry, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logger.Error("failed to close rows channel", "err", err)
+		}
+	}()
+
+	var result []brandeddomain.Domain
+	for rows.Next() {
+		var domain brandeddomain.Domain
+		if err := rows.Scan(&domain.Host, &domain.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, domain)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *DomainRepository) Delete(ctx context.Context, host string) error {
+	query := `DELETE FROM domains WHERE host = $1`
+
+	res, err := r.db.ExecWithRetry(ctx, r.retry, query, host)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && string(pqErr.Code) == "23503" {
+			return brandeddomain.ErrDomainInUse
+		}
+
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return brandeddomain.ErrDomainNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+type ShortCodeCounterRepository struct {
+	db    *dbpg.DB
+	retry retry.Strategy
+}
+
+func NewShortCodeCounterRepository(db *dbpg.DB, retry retry.Strategy) *ShortCodeCounterRepository {
+	return &ShortCodeCounterRepository{
+		db:    db,
+		retry: retry,
+	}
+}
+
+// ReserveBlock runs on the master so the counter update is never routed to a replica.
+// A retried reservation at worst skips a block, it never hands out the same IDs twice.
+func (r *ShortCodeCounterRepository) ReserveBlock(ctx context.Context, size int64) (int64, error) {
+	query := `UPDATE short_code_counter SET value = value + $1 RETURNING value - $1`
+
+	var start int64
+	err := retry.DoContext(ctx, r.retry, func() error {
+		return r.db.Master.QueryRowContext(ctx, query, size).Scan(&start)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return start, nil
+}
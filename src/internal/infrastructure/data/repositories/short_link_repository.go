@@ -2,9 +2,13 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	shortlink "shortener/src/internal/domain/short_link"
 	"shortener/src/pkg/logger"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -25,22 +29,100 @@ func NewShortLinkRepository(db *dbpg.DB, retry retry.Strategy) *ShortLinkReposit
 	}
 }
 
-func (r *ShortLinkRepository) Create(ctx context.Context, shortURL, originalURL string) (*shortlink.ShortLink, error) {
+const shortLinkColumns = `id, COALESCE(domain, ''), short_code, original_url, raw_url, created_at, expires_at,
+	COALESCE(fallback_url, ''), max_clicks, clicks_used, COALESCE(password_hash, ''), deleted_at, owner_id, total_clicks,
+	redirect_code, query_passthrough, path_passthrough, rules, variants, sticky_variants, deep_link, always_preview,
+	COALESCE(title, ''), COALESCE(description, ''),
+	ARRAY(SELECT t.name FROM short_link_tags lt JOIN tags t ON t.id = lt.tag_id
+		WHERE lt.link_id = short_links.id ORDER BY t.name), metadata`
+
+const createBatchChunkSize = 500
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *ShortLinkRepository) Create(
+	ctx context.Context,
+	params shortlink.CreateParams,
+) (*shortlink.ShortLink, error) {
 	shortLink := &shortlink.ShortLink{
-		ID:          uuid.New(),
-		ShortCode:   shortURL,
-		OriginalURL: originalURL,
-		CreatedAt:   time.Now().UTC(),
+		ID:               uuid.New(),
+		Domain:           params.Domain,
+		ShortCode:        params.ShortCode,
+		OriginalURL:      params.OriginalURL,
+		RawURL:           params.RawURL,
+		CreatedAt:        time.Now().UTC(),
+		ExpiresAt:        params.ExpiresAt,
+		FallbackURL:      params.FallbackURL,
+		MaxClicks:        params.MaxClicks,
+		PasswordHash:     params.PasswordHash,
+		OwnerID:          params.OwnerID,
+		RedirectCode:     params.RedirectCode,
+		QueryPassthrough: params.QueryPassthrough,
+		PathPassthrough:  params.PathPassthrough,
+		Rules:            params.Rules,
+		Variants:         params.Variants,
+		StickyVariants:   params.StickyVariants,
+		DeepLink:         params.DeepLink,
+		AlwaysPreview:    params.AlwaysPreview,
+		Title:            params.Title,
+		Description:      params.Description,
 	}
 
-	query := `INSERT INTO short_links (id, short_code, original_url, created_at) VALUES ($1, $2, $3, $4)`
+	rules, err := encodeJSONList(shortLink.Rules)
+	if err != nil {
+		return nil, err
+	}
 
-	_, err := r.db.ExecWithRetry(ctx, r.retry,
+	variants, err := encodeJSONList(shortLink.Variants)
+	if err != nil {
+		return nil, err
+	}
+
+	deepLink, err := encodeDeepLink(shortLink.DeepLink)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO short_links (
+                    id, short_code, original_url, created_at, expires_at, fallback_url, max_clicks, password_hash,
+                    owner_id, target_hash, target_domain, dedupe, domain, raw_url, redirect_code,
+                    query_passthrough, path_passthrough, rules, variants, sticky_variants, deep_link, always_preview,
+                    title, description
+                    ) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, NULLIF($8, ''), $9, $10, NULLIF($11, ''), $12,
+                    NULLIF($13, ''), $14, $15, $16, $17, $18, $19, $20, $21, $22, NULLIF($23, ''), NULLIF($24, ''))`
+
+	if params.Dedupe {
+		query += ` ON CONFLICT (owner_id, domain, target_hash) WHERE dedupe AND deleted_at IS NULL DO NOTHING`
+	}
+
+	res, err := r.db.ExecWithRetry(ctx, r.retry,
 		query,
 		shortLink.ID,
 		shortLink.ShortCode,
 		shortLink.OriginalURL,
 		shortLink.CreatedAt,
+		shortLink.ExpiresAt,
+		shortLink.FallbackURL,
+		shortLink.MaxClicks,
+		shortLink.PasswordHash,
+		shortLink.OwnerID,
+		params.TargetHash,
+		params.TargetDomain,
+		params.Dedupe,
+		shortLink.Domain,
+		shortLink.RawURL,
+		shortLink.RedirectCode,
+		shortLink.QueryPassthrough,
+		shortLink.PathPassthrough,
+		rules,
+		variants,
+		shortLink.StickyVariants,
+		deepLink,
+		shortLink.AlwaysPreview,
+		shortLink.Title,
+		shortLink.Description,
 	)
 
 	if err != nil {
@@ -50,26 +132,680 @@ func (r *ShortLinkRepository) Create(ctx context.Context, shortURL, originalURL
 			return nil, shortlink.ErrShortLinkAlreadyExists
 		}
 
+		if errors.As(err, &pqErr) && string(pqErr.Code) == "23503" {
+			return nil, shortlink.ErrUnknownDomain
+		}
+
+		return nil, err
+	}
+
+	if params.Dedupe {
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+
+		if affected == 0 {
+			return nil, shortlink.ErrTargetAlreadyShortened
+		}
+	}
+
+	return shortLink, nil
+}
+
+func (r *ShortLinkRepository) CreateBatch(
+	ctx context.Context,
+	params []shortlink.CreateParams,
+) ([]shortlink.CreateResult, error) {
+	results := make([]shortlink.CreateResult, 0, len(params))
+	for start := 0; start < len(params); start += createBatchChunkSize {
+		end := min(start+createBatchChunkSize, len(params))
+
+		chunk, err := r.createChunk(ctx, params[start:end])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, chunk...)
+	}
+
+	return results, nil
+}
+
+func (r *ShortLinkRepository) createChunk(
+	ctx context.Context,
+	params []shortlink.CreateParams,
+) ([]shortlink.CreateResult, error) {
+	const columnsCount = 23
+
+	links := make([]*shortlink.ShortLink, len(params))
+	values := make([]string, len(params))
+	args := make([]any, 0, len(params)*columnsCount)
+	createdAt := time.Now().UTC()
+
+	for i, p := range params {
+		links[i] = &shortlink.ShortLink{
+			ID:               uuid.New(),
+			Domain:           p.Domain,
+			ShortCode:        p.ShortCode,
+			OriginalURL:      p.OriginalURL,
+			RawURL:           p.RawURL,
+			CreatedAt:        createdAt,
+			ExpiresAt:        p.ExpiresAt,
+			FallbackURL:      p.FallbackURL,
+			MaxClicks:        p.MaxClicks,
+			PasswordHash:     p.PasswordHash,
+			OwnerID:          p.OwnerID,
+			RedirectCode:     p.RedirectCode,
+			QueryPassthrough: p.QueryPassthrough,
+			PathPassthrough:  p.PathPassthrough,
+			Rules:            p.Rules,
+			Variants:         p.Variants,
+			StickyVariants:   p.StickyVariants,
+			DeepLink:         p.DeepLink,
+			AlwaysPreview:    p.AlwaysPreview,
+			Title:            p.Title,
+			Description:      p.Description,
+		}
+
+		rules, err := encodeJSONList(p.Rules)
+		if err != nil {
+			return nil, err
+		}
+
+		variants, err := encodeJSONList(p.Variants)
+		if err != nil {
+			return nil, err
+		}
+
+		deepLink, err := encodeDeepLink(p.DeepLink)
+		if err != nil {
+			return nil, err
+		}
+
+		n := i * columnsCount
+		values[i] = fmt.Sprintf(
+			"($%d, $%d, $%d, $%d, $%d, NULLIF($%d, ''), $%d, NULLIF($%d, ''), $%d, $%d, NULLIF($%d, ''), "+
+				"NULLIF($%d, ''), $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, NULLIF($%d, ''), NULLIF($%d, ''))",
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11, n+12, n+13, n+14, n+15, n+16, n+17, n+18,
+			n+19, n+20, n+21, n+22, n+23,
+		)
+		args = append(args,
+			links[i].ID,
+			links[i].ShortCode,
+			links[i].OriginalURL,
+			links[i].CreatedAt,
+			links[i].ExpiresAt,
+			links[i].FallbackURL,
+			links[i].MaxClicks,
+			links[i].PasswordHash,
+			links[i].OwnerID,
+			p.TargetHash,
+			p.TargetDomain,
+			links[i].Domain,
+			links[i].RawURL,
+			links[i].RedirectCode,
+			links[i].QueryPassthrough,
+			links[i].PathPassthrough,
+			rules,
+			variants,
+			links[i].StickyVariants,
+			deepLink,
+			links[i].AlwaysPreview,
+			links[i].Title,
+			links[i].Description,
+		)
+	}
+
+	query := `INSERT INTO short_links (
+                    id, short_code, original_url, created_at, expires_at, fallback_url, max_clicks, password_hash,
+                    owner_id, target_hash, target_domain, domain, raw_url, redirect_code, query_passthrough,
+                    path_passthrough, rules, variants, sticky_variants, deep_link, always_preview, title, description
+                    ) VALUES ` + strings.Join(values, ", ") + `
+				ON CONFLICT DO NOTHING
+				RETURNING id`
+
+	// Not retried: a lost but committed attempt would make its rows look like conflicts on the next one.
+	rows, err := r.db.Master.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logger.Error("failed to close rows channel", "err", err)
+		}
+	}()
+
+	inserted := make(map[uuid.UUID]struct{}, len(params))
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		inserted[id] = struct{}{}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]shortlink.CreateResult, len(params))
+	for i, link := range links {
+		if _, ok := inserted[link.ID]; ok {
+			results[i].Link = link
+		} else {
+			results[i].Err = shortlink.ErrShortLinkAlreadyExists
+		}
+	}
+
+	return results, nil
+}
+
+func (r *ShortLinkRepository) Get(ctx context.Context, domain, shortURL string) (*shortlink.ShortLink, error) {
+	query := `SELECT ` + shortLinkColumns + ` FROM short_links WHERE short_code = $1 AND ` + domainCondition(domain, 2)
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.retry, query, shortURL, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	shortLink, err := scanShortLink(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shortlink.ErrShortLinkNotFound
+		}
+
+		return nil, err
+	}
+
+	return shortLink, nil
+}
+
+func (r *ShortLinkRepository) AvailableCodes(ctx context.Context, domain string, codes []string) ([]string, error) {
+	query := `SELECT c.code FROM unnest($1::text[]) WITH ORDINALITY AS c(code, position)
+				WHERE NOT EXISTS (
+					SELECT 1 FROM short_links WHERE short_code = c.code AND ` + domainCondition(domain, 2) + `
+				)
+				ORDER BY c.position`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retry, query, pq.Array(codes), domain)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logger.Error("failed to close rows channel", "err", err)
+		}
+	}()
+
+	var available []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		available = append(available, code)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return available, nil
+}
+
+func (r *ShortLinkRepository) List(
+	ctx context.Context,
+	params shortlink.ListParams,
+) (*shortlink.ListPage, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	filter := params.Filter
+	if filter.OwnerID != nil {
+		conditions = append(conditions, "owner_id = "+arg(*filter.OwnerID))
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= "+arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "created_at < "+arg(*filter.CreatedTo))
+	}
+	if filter.TargetDomain != "" {
+		conditions = append(conditions, "target_domain = "+arg(filter.TargetDomain))
+	}
+	if filter.TargetContains != "" {
+		conditions = append(conditions, `original_url ILIKE `+arg("%"+escapeLike(filter.TargetContains)+"%"))
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM short_link_tags lt JOIN tags t ON t.id = lt.tag_id
+				WHERE lt.link_id = short_links.id AND t.name = `+arg(filter.Tag)+`)`)
+	}
+
+	sortColumn := "created_at"
+	if params.Sort == shortlink.ListSortTotalClicks {
+		sortColumn = "total_clicks"
+	}
+
+	direction, comparison := "DESC", "<"
+	if params.Ascending {
+		direction, comparison = "ASC", ">"
+	}
+
+	if cursor := params.Cursor; cursor != nil {
+		var sortValue any = cursor.CreatedAt
+		if params.Sort == shortlink.ListSortTotalClicks {
+			sortValue = cursor.TotalClicks
+		}
+
+		conditions = append(conditions, fmt.Sprintf(
+			"(%s, id) %s (%s, %s)", sortColumn, comparison, arg(sortValue), arg(cursor.ID),
+		))
+	}
+
+	query := `SELECT ` + shortLinkColumns + ` FROM short_links
+				WHERE ` + strings.Join(conditions, " AND ") + `
+				ORDER BY ` + sortColumn + ` ` + direction + `, id ` + direction + `
+				LIMIT ` + arg(params.Limit+1)
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retry, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logger.Error("failed to close rows channel", "err", err)
+		}
+	}()
+
+	page := &shortlink.ListPage{}
+	for rows.Next() {
+		shortLink, err := scanShortLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Links = append(page.Links, *shortLink)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Links) > params.Limit {
+		page.Links = page.Links[:params.Limit]
+		last := page.Links[len(page.Links)-1]
+		page.NextCursor = &shortlink.ListCursor{
+			CreatedAt:   last.CreatedAt,
+			TotalClicks: last.TotalClicks,
+			ID:          last.ID,
+		}
+	}
+
+	return page, nil
+}
+
+func (r *ShortLinkRepository) GetDeduplicated(
+	ctx context.Context,
+	ownerID *uuid.UUID,
+	domain, targetHash string,
+) (*shortlink.ShortLink, error) {
+	query := `SELECT ` + shortLinkColumns + ` FROM short_links
+				WHERE owner_id IS NOT DISTINCT FROM $1 AND target_hash = $2 AND ` + domainCondition(domain, 3) + `
+				AND dedupe AND deleted_at IS NULL`
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.retry, query, ownerID, targetHash, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	shortLink, err := scanShortLink(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shortlink.ErrShortLinkNotFound
+		}
+
 		return nil, err
 	}
 
 	return shortLink, nil
 }
 
-func (r *ShortLinkRepository) Get(ctx context.Context, shortURL string) (*shortlink.ShortLink, error) {
-	query := `SELECT * FROM short_links WHERE short_code = $1`
+func (r *ShortLinkRepository) RetireDeduplicated(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE short_links SET dedupe = FALSE WHERE id = $1`
+
+	_, err := r.db.ExecWithRetry(ctx, r.retry, query, id)
+	return err
+}
+
+func (r *ShortLinkRepository) ConsumeClick(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE short_links SET clicks_used = clicks_used + 1
+				WHERE id = $1 AND (max_clicks IS NULL OR clicks_used < max_clicks)`
 
-	row, err := r.db.QueryRowWithRetry(ctx, r.retry, query, shortURL)
+	res, err := r.db.ExecWithRetry(ctx, r.retry, query, id)
 	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return shortlink.ErrShortLinkExhausted
+	}
+
+	return nil
+}
+
+func (r *ShortLinkRepository) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	params shortlink.UpdateParams,
+) (*shortlink.ShortLink, error) {
+	query := `UPDATE short_links SET original_url = COALESCE($2, original_url),
+				raw_url = COALESCE($3, raw_url),
+				target_hash = COALESCE($4, target_hash),
+				target_domain = COALESCE(NULLIF($5, ''), target_domain),
+				redirect_code = COALESCE($6, redirect_code),
+				query_passthrough = COALESCE($7, query_passthrough),
+				path_passthrough = COALESCE($8, path_passthrough),
+				rules = COALESCE($9::jsonb, rules),
+				variants = COALESCE($10::jsonb, variants),
+				sticky_variants = COALESCE($11, sticky_variants),
+				deep_link = COALESCE($12::jsonb, deep_link),
+				always_preview = COALESCE($13, always_preview),
+				title = CASE WHEN $14::text IS NULL THEN title ELSE NULLIF($14, '') END,
+				description = CASE WHEN $15::text IS NULL THEN description ELSE NULLIF($15, '') END,
+				metadata = CASE WHEN $2 <> original_url THEN NULL ELSE metadata END
+				WHERE id = $1 AND deleted_at IS NULL
+				RETURNING ` + shortLinkColumns
+
+	var rules, variants, deepLink *string
+	if params.Rules != nil {
+		encoded, err := encodeJSONList(*params.Rules)
+		if err != nil {
+			return nil, err
+		}
+		rules = &encoded
+	}
+
+	if params.Variants != nil {
+		encoded, err := encodeJSONList(*params.Variants)
+		if err != nil {
+			return nil, err
+		}
+		variants = &encoded
+	}
+
+	if params.DeepLink != nil {
+		encoded, err := encodeDeepLink(params.DeepLink)
+		if err != nil {
+			return nil, err
+		}
+		deepLink = encoded
+	}
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.retry, query,
+		id,
+		params.OriginalURL,
+		params.RawURL,
+		params.TargetHash,
+		params.TargetDomain,
+		params.RedirectCode,
+		params.QueryPassthrough,
+		params.PathPassthrough,
+		rules,
+		variants,
+		params.StickyVariants,
+		deepLink,
+		params.AlwaysPreview,
+		params.Title,
+		params.Description,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	shortLink, err := scanShortLink(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shortlink.ErrShortLinkNotFound
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && string(pqErr.Code) == "23505" {
+			return nil, shortlink.ErrTargetAlreadyShortened
+		}
+
 		return nil, err
 	}
 
+	return shortLink, nil
+}
+
+func (r *ShortLinkRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE short_links SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+
+	res, err := r.db.ExecWithRetry(ctx, r.retry, query, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return shortlink.ErrShortLinkNotFound
+	}
+
+	return nil
+}
+
+func (r *ShortLinkRepository) AttachTags(ctx context.Context, linkID, ownerID uuid.UUID, tags []string) error {
+	// The no-op update makes RETURNING yield the ids of tags the owner already has.
+	query := `WITH upserted AS (
+					INSERT INTO tags (owner_id, name) SELECT $2, unnest($3::text[])
+					ON CONFLICT (owner_id, name) DO UPDATE SET name = EXCLUDED.name
+					RETURNING id
+				)
+				INSERT INTO short_link_tags (link_id, tag_id) SELECT $1, id FROM upserted
+				ON CONFLICT DO NOTHING`
+
+	_, err := r.db.ExecWithRetry(ctx, r.retry, query, linkID, ownerID, pq.Array(tags))
+
+	return err
+}
+
+func (r *ShortLinkRepository) DetachTag(ctx context.Context, linkID, ownerID uuid.UUID, tag string) error {
+	query := `DELETE FROM short_link_tags
+				WHERE link_id = $1 AND tag_id = (SELECT id FROM tags WHERE owner_id = $2 AND name = $3)`
+
+	res, err := r.db.ExecWithRetry(ctx, r.retry, query, linkID, ownerID, tag)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return shortlink.ErrTagNotFound
+	}
+
+	return nil
+}
+
+func (r *ShortLinkRepository) IDsByTag(ctx context.Context, ownerID *uuid.UUID, tag string) ([]uuid.UUID, error) {
+	query := `SELECT l.id FROM short_links l
+				JOIN short_link_tags lt ON lt.link_id = l.id
+				JOIN tags t ON t.id = lt.tag_id
+				WHERE t.name = $1 AND ($2::uuid IS NULL OR t.owner_id = $2) AND l.deleted_at IS NULL`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retry, query, tag, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logger.Error("failed to close rows channel", "err", err)
+		}
+	}()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *ShortLinkRepository) SetMetadata(
+	ctx context.Context,
+	id uuid.UUID,
+	targetURL string,
+	metadata shortlink.Metadata,
+) error {
+	bytes, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE short_links SET metadata = $3::jsonb
+				WHERE id = $1 AND original_url = $2 AND deleted_at IS NULL`
+
+	_, err = r.db.ExecWithRetry(ctx, r.retry, query, id, targetURL, string(bytes))
+
+	return err
+}
+
+// domainCondition matches the default domain with IS NULL so both branches can use the (domain, short_code) index.
+// The placeholder is always referenced to keep the argument count stable.
+func domainCondition(domain string, placeholder int) string {
+	if domain == "" {
+		return fmt.Sprintf("domain IS NULL AND $%d = ''", placeholder)
+	}
+
+	return fmt.Sprintf("domain = $%d", placeholder)
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func scanShortLink(row rowScanner) (*shortlink.ShortLink, error) {
 	var shortLink shortlink.ShortLink
+	var rules, variants, deepLink, metadata []byte
 
-	err = row.Scan(&shortLink.ID, &shortLink.ShortCode, &shortLink.OriginalURL, &shortLink.CreatedAt)
+	err := row.Scan(
+		&shortLink.ID,
+		&shortLink.Domain,
+		&shortLink.ShortCode,
+		&shortLink.OriginalURL,
+		&shortLink.RawURL,
+		&shortLink.CreatedAt,
+		&shortLink.ExpiresAt,
+		&shortLink.FallbackURL,
+		&shortLink.MaxClicks,
+		&shortLink.ClicksUsed,
+		&shortLink.PasswordHash,
+		&shortLink.DeletedAt,
+		&shortLink.OwnerID,
+		&shortLink.TotalClicks,
+		&shortLink.RedirectCode,
+		&shortLink.QueryPassthrough,
+		&shortLink.PathPassthrough,
+		&rules,
+		&variants,
+		&shortLink.StickyVariants,
+		&deepLink,
+		&shortLink.AlwaysPreview,
+		&shortLink.Title,
+		&shortLink.Description,
+		pq.Array(&shortLink.Tags),
+		&metadata,
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := json.Unmarshal(rules, &shortLink.Rules); err != nil {
+		return nil, fmt.Errorf("decode rules of short link %s: %w", shortLink.ID, err)
+	}
+
+	if err := json.Unmarshal(variants, &shortLink.Variants); err != nil {
+		return nil, fmt.Errorf("decode variants of short link %s: %w", shortLink.ID, err)
+	}
+
+	if deepLink != nil {
+		if err := json.Unmarshal(deepLink, &shortLink.DeepLink); err != nil {
+			return nil, fmt.Errorf("decode deep link of short link %s: %w", shortLink.ID, err)
+		}
+	}
+
+	if metadata != nil {
+		if err := json.Unmarshal(metadata, &shortLink.Metadata); err != nil {
+			return nil, fmt.Errorf("decode metadata of short link %s: %w", shortLink.ID, err)
+		}
+	}
+
 	return &shortLink, nil
 }
+
+// encodeJSONList returns the JSON of a JSONB list column as a string: lib/pq would send a byte slice as bytea.
+func encodeJSONList[T any](items []T) (string, error) {
+	if len(items) == 0 {
+		return "[]", nil
+	}
+
+	bytes, err := json.Marshal(items)
+	if err != nil {
+		return "", err
+	}
+
+	return string(bytes), nil
+}
+
+// encodeDeepLink returns the JSON of a deep link as a string, or nil for SQL NULL. An empty deep link
+// becomes JSON null, which Update stores to remove the deep link.
+func encodeDeepLink(deepLink *shortlink.DeepLink) (*string, error) {
+	if deepLink == nil {
+		return nil, nil
+	}
+
+	if deepLink.Empty() {
+		encoded := "null"
+		return &encoded, nil
+	}
+
+	bytes, err := json.Marshal(deepLink)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := string(bytes)
+	return &encoded, nil
+}
@@ -7,6 +7,7 @@ import (
 	"shortener/src/pkg/logger"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/wb-go/wbf/dbpg"
 	"github.com/wb-go/wbf/retry"
@@ -36,30 +37,42 @@ func (r *VisitRepository) CreateBatch(ctx context.Context, visits []visit.Visit)
 				return
 			}
 			query := fmt.Sprintf(
-				`INSERT INTO visits (
-                    id, link_id, created_at, user_agent, ip_address
-                    ) VALUES (%s, %s, %s, %s, %s) ON CONFLICT DO NOTHING`,
+				`WITH inserted AS (
+                    INSERT INTO visits (
+                    id, link_id, created_at, user_agent, ip_address, rule, variant, handoff, source
+                    ) VALUES (%s, %s, %s, %s, %s, NULLIF(%s, ''), NULLIF(%s, ''), NULLIF(%s, ''), NULLIF(%s, ''))
+                    ON CONFLICT DO NOTHING
+                    RETURNING link_id
+                    )
+				UPDATE short_links SET total_clicks = total_clicks + 1
+				WHERE id IN (SELECT link_id FROM inserted)`,
 				pq.QuoteLiteral(visit.ID.String()),
 				pq.QuoteLiteral(visit.LinkID.String()),
 				pq.QuoteLiteral(visit.CreatedAt.Format(timeFormat)),
 				pq.QuoteLiteral(visit.UserAgent),
 				pq.QuoteLiteral(visit.IPAddress),
+				pq.QuoteLiteral(visit.Rule),
+				pq.QuoteLiteral(visit.Variant),
+				pq.QuoteLiteral(visit.Handoff),
+				pq.QuoteLiteral(visit.Source),
 			)
 			visitsChan <- query
 		}
 	}()
 }
 
-func (r *VisitRepository) AnalyticsAggregatedByDay(ctx context.Context, shortURL string) ([]visit.PeriodCount, error) {
+func (r *VisitRepository) AnalyticsAggregatedByDay(
+	ctx context.Context,
+	linkIDs ...uuid.UUID,
+) ([]visit.PeriodCount, error) {
 	query := `SELECT date_trunc('day', visits.created_at) as day, count(*) as count
 				FROM visits
-				JOIN public.short_links sl on sl.id = visits.link_id
-				WHERE sl.short_code = $1
+				WHERE visits.link_id = ANY($1::uuid[])
 				GROUP BY day
 				order by day
 				`
 
-	rows, err := r.db.QueryWithRetry(ctx, r.retry, query, shortURL)
+	rows, err := r.db.QueryWithRetry(ctx, r.retry, query, linkIDArray(linkIDs))
 	if err != nil {
 		return nil, err
 	}
@@ -89,17 +102,16 @@ func (r *VisitRepository) AnalyticsAggregatedByDay(ctx context.Context, shortURL
 
 func (r *VisitRepository) AnalyticsAggregatedByMonth(
 	ctx context.Context,
-	shortURL string,
+	linkIDs ...uuid.UUID,
 ) ([]visit.PeriodCount, error) {
 	query := `SELECT date_trunc('month', visits.created_at) as month, count(*) as count
 				FROM visits
-				JOIN public.short_links sl on sl.id = visits.link_id
-				WHERE sl.short_code = $1
+				WHERE visits.link_id = ANY($1::uuid[])
 				GROUP BY month
 				order by month
 				`
 
-	rows, err := r.db.QueryWithRetry(ctx, r.retry, query, shortURL)
+	rows, err := r.db.QueryWithRetry(ctx, r.retry, query, linkIDArray(linkIDs))
 	if err != nil {
 		return nil, err
 	}
@@ -129,16 +141,15 @@ func (r *VisitRepository) AnalyticsAggregatedByMonth(
 
 func (r *VisitRepository) AnalyticsAggregatedByUserAgent(
 	ctx context.Context,
-	shortURL string,
+	linkIDs ...uuid.UUID,
 ) ([]visit.UserAgentCount, error) {
 	query := `SELECT user_agent, count(*) as count
 				FROM visits
-				JOIN public.short_links sl on sl.id = visits.link_id
-				WHERE sl.short_code = $1
+				WHERE visits.link_id = ANY($1::uuid[])
 				GROUP BY user_agent
 				`
 
-	rows, err := r.db.QueryWithRetry(ctx, r.retry, query, shortURL)
+	rows, err := r.db.QueryWithRetry(ctx, r.retry, query, linkIDArray(linkIDs))
 	if err != nil {
 		return nil, err
 	}
@@ -165,3 +176,107 @@ func (r *VisitRepository) AnalyticsAggregatedByUserAgent(
 
 	return result, nil
 }
+
+func (r *VisitRepository) AnalyticsAggregatedByVariant(
+	ctx context.Context,
+	linkID uuid.UUID,
+) ([]visit.VariantCount, error) {
+	query := `SELECT variant, count(*) as count
+				FROM visits
+				WHERE visits.link_id = $1 AND visits.variant IS NOT NULL
+				GROUP BY variant
+				ORDER BY variant
+				`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retry, query, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logger.Error("failed to close rows channel", "err", err)
+		}
+	}()
+
+	var result []visit.VariantCount
+	for rows.Next() {
+		var variant string
+		var count int64
+		if err := rows.Scan(&variant, &count); err != nil {
+			return nil, err
+		}
+		result = append(result, visit.VariantCount{
+			Variant: variant,
+			Count:   count,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *VisitRepository) AnalyticsAggregatedBySource(
+	ctx context.Context,
+	linkIDs ...uuid.UUID,
+) ([]visit.SourceCount, error) {
+	query := `SELECT COALESCE(source, $2) as source, count(*) as count
+				FROM visits
+				WHERE visits.link_id = ANY($1::uuid[])
+				GROUP BY 1
+				ORDER BY 1
+				`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retry, query, linkIDArray(linkIDs), visit.SourceDirect)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logger.Error("failed to close rows channel", "err", err)
+		}
+	}()
+
+	var result []visit.SourceCount
+	for rows.Next() {
+		var source string
+		var count int64
+		if err := rows.Scan(&source, &count); err != nil {
+			return nil, err
+		}
+		result = append(result, visit.SourceCount{
+			Source: source,
+			Count:  count,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *VisitRepository) TotalVisits(ctx context.Context, linkID uuid.UUID) (int64, error) {
+	query := `SELECT total_clicks FROM short_links WHERE id = $1`
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.retry, query, linkID)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	if err := row.Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// linkIDArray passes link ids as text: lib/pq cannot encode a slice of uuid.UUID.
+func linkIDArray(linkIDs []uuid.UUID) any {
+	ids := make([]string, len(linkIDs))
+	for i, id := range linkIDs {
+		ids[i] = id.String()
+	}
+
+	return pq.Array(ids)
+}
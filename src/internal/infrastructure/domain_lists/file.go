@@ -0,0 +1,134 @@
+package domainlists
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"shortener/src/pkg/logger"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type lists struct {
+	allow []string
+	block []string
+}
+
+// FileLists reads a domain allowlist and blocklist from a local file and reloads it when the file changes.
+// Each line is "allow <domain>" or "block <domain>"; empty lines and lines starting with '#' are skipped.
+type FileLists struct {
+	path    string
+	modTime time.Time
+	current atomic.Pointer[lists]
+}
+
+// NewFileLists loads the file at path; an empty path gives empty lists that never change.
+func NewFileLists(path string) (*FileLists, error) {
+	f := &FileLists{path: path}
+	f.current.Store(&lists{})
+
+	if path == "" {
+		return f, nil
+	}
+
+	if err := f.reload(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *FileLists) Lists() (allow, block []string) {
+	current := f.current.Load()
+	return current.allow, current.block
+}
+
+// Watch checks the file for changes every interval until ctx is done. A file that fails to parse
+// is logged and the previous lists stay in effect.
+func (f *FileLists) Watch(ctx context.Context, interval time.Duration) {
+	if f.path == "" {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := f.reload(); err != nil {
+				logger.Error("failed to reload domain lists", "path", f.path, "err", err)
+			}
+		}
+	}
+}
+
+func (f *FileLists) reload() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+
+	if info.ModTime().Equal(f.modTime) {
+		return nil
+	}
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := file.Close(); err != nil {
+			logger.Error("failed to close domain lists file", "err", err)
+		}
+	}()
+
+	parsed, err := parse(file)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", f.path, err)
+	}
+
+	f.current.Store(parsed)
+	f.modTime = info.ModTime()
+	logger.Info("domain lists loaded", "allow", len(parsed.allow), "block", len(parsed.block))
+
+	return nil
+}
+
+func parse(file *os.File) (*lists, error) {
+	parsed := &lists{}
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected \"allow <domain>\" or \"block <domain>\"", line)
+		}
+
+		domain := strings.TrimSuffix(strings.ToLower(fields[1]), ".")
+		switch fields[0] {
+		case "allow":
+			parsed.allow = append(parsed.allow, domain)
+		case "block":
+			parsed.block = append(parsed.block, domain)
+		default:
+			return nil, fmt.Errorf("line %d: unknown list %q", line, fields[0])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
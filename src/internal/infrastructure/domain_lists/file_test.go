@@ -0,0 +1,62 @@
+package domainlists_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domainlists "shortener/src/internal/infrastructure/domain_lists"
+)
+
+func TestFileLists_LoadsAndReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lists.txt")
+	if err := os.WriteFile(path, []byte("# lists\nallow Example.com\nblock evil.com.\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	lists, err := domainlists.NewFileLists(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	allow, block := lists.Lists()
+	if len(allow) != 1 || allow[0] != "example.com" || len(block) != 1 || block[0] != "evil.com" {
+		t.Fatalf("unexpected lists: allow=%v block=%v", allow, block)
+	}
+
+	if err := os.WriteFile(path, []byte("block other.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go lists.Watch(ctx, 10*time.Millisecond)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		allow, block = lists.Lists()
+		if len(allow) == 0 && len(block) == 1 && block[0] == "other.com" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("lists were not reloaded: allow=%v block=%v", allow, block)
+}
+
+func TestFileLists_RejectsMalformedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lists.txt")
+	if err := os.WriteFile(path, []byte("deny evil.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := domainlists.NewFileLists(path); err == nil {
+		t.Fatal("expected an error for an unknown list")
+	}
+}
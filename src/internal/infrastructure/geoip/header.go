@@ -0,0 +1,39 @@
+package geoip
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HeaderResolver takes the visitor country from a header set by the CDN or reverse proxy in front of the
+// service, such as Cloudflare's CF-IPCountry. The proxy must overwrite the header, otherwise clients can
+// choose their country.
+type HeaderResolver struct {
+	headers []string
+}
+
+// NewHeaderResolver checks headers in order and uses the first one holding a country code; no headers
+// disables country lookup.
+func NewHeaderResolver(headers []string) *HeaderResolver {
+	return &HeaderResolver{headers: headers}
+}
+
+func (r *HeaderResolver) Country(_ string, header http.Header) string {
+	for _, name := range r.headers {
+		country := strings.ToUpper(strings.TrimSpace(header.Get(name)))
+		if validCountry(country) {
+			return country
+		}
+	}
+
+	return ""
+}
+
+// validCountry accepts two-letter codes except XX and T1, which Cloudflare uses for unknown and Tor.
+func validCountry(country string) bool {
+	if len(country) != 2 || country == "XX" || country == "T1" {
+		return false
+	}
+
+	return country[0] >= 'A' && country[0] <= 'Z' && country[1] >= 'A' && country[1] <= 'Z'
+}
@@ -0,0 +1,31 @@
+package geoip_test
+
+import (
+	"net/http"
+	"testing"
+
+	"shortener/src/internal/infrastructure/geoip"
+)
+
+func TestHeaderResolver_Country(t *testing.T) {
+	resolver := geoip.NewHeaderResolver([]string{"CF-IPCountry", "X-Country-Code"})
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   string
+	}{
+		{name: "first header", header: http.Header{"Cf-Ipcountry": {"de"}, "X-Country-Code": {"FR"}}, want: "DE"},
+		{name: "unknown falls through", header: http.Header{"Cf-Ipcountry": {"XX"}, "X-Country-Code": {"FR"}}, want: "FR"},
+		{name: "invalid value", header: http.Header{"X-Country-Code": {"France"}}, want: ""},
+		{name: "no header", header: http.Header{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolver.Country("203.0.113.1", tt.header); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	shortlink "shortener/src/internal/domain/short_link"
+	"shortener/src/pkg/logger"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+	wbfkafka "github.com/wb-go/wbf/kafka"
+	"github.com/wb-go/wbf/retry"
+)
+
+// MetadataConsumer fetches link metadata with several workers. Workers commit independently, so a crash may
+// skip requests queued before a committed one; the metadata is best effort and a target change requests it again.
+type MetadataConsumer struct {
+	consumer        *wbfkafka.Consumer
+	metadataService shortlink.MetadataService
+	retry           retry.Strategy
+	workers         int
+	wg              sync.WaitGroup
+}
+
+func NewMetadataConsumer(
+	consumer *wbfkafka.Consumer,
+	metadataService shortlink.MetadataService,
+	retry retry.Strategy,
+	workers int,
+) *MetadataConsumer {
+	return &MetadataConsumer{
+		consumer:        consumer,
+		metadataService: metadataService,
+		retry:           retry,
+		workers:         max(workers, 1),
+	}
+}
+
+func (c *MetadataConsumer) Start(ctx context.Context) {
+	msgs := make(chan kafka.Message)
+	c.consumer.StartConsuming(ctx, msgs, c.retry)
+
+	for range c.workers {
+		c.wg.Go(func() {
+			c.run(ctx, msgs)
+		})
+	}
+}
+
+// Stop waits for the fetches in progress; the context passed to Start has to be cancelled first.
+func (c *MetadataConsumer) Stop() error {
+	c.wg.Wait()
+	return c.consumer.Close()
+}
+
+func (c *MetadataConsumer) run(ctx context.Context, msgs <-chan kafka.Message) {
+	for msg := range msgs {
+		var req shortlink.MetadataRequest
+		if err := json.Unmarshal(msg.Value, &req); err != nil {
+			logger.Error("failed to unmarshal metadata request", "err", err)
+		} else if err := c.metadataService.Fetch(ctx, req); err != nil {
+			// Left uncommitted on shutdown so the request is fetched after a restart.
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.Error("failed to fetch link metadata", "link_id", req.LinkID, "url", req.URL, "err", err)
+		}
+
+		if err := c.consumer.Commit(ctx, msg); err != nil {
+			logger.Error("failed to commit metadata request", "err", err)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package kafka
 
 import (
 	"context"
+	"shortener/src/internal/application/contracts"
 
+	"github.com/segmentio/kafka-go"
 	wbfkafka "github.com/wb-go/wbf/kafka"
 	"github.com/wb-go/wbf/retry"
 )
@@ -22,3 +24,15 @@ func NewKafkaProducer(producer *wbfkafka.Producer, retry retry.Strategy) *Produc
 func (p *Producer) Produce(ctx context.Context, key, value []byte) error {
 	return p.producer.SendWithRetry(ctx, p.rerty, key, value)
 }
+
+// ProduceBatch writes the messages in one request instead of waiting out the writer's batch timeout per message.
+func (p *Producer) ProduceBatch(ctx context.Context, messages []contracts.Message) error {
+	msgs := make([]kafka.Message, len(messages))
+	for i, message := range messages {
+		msgs[i] = kafka.Message{Key: message.Key, Value: message.Value}
+	}
+
+	return retry.Do(func() error {
+		return p.producer.Writer.WriteMessages(ctx, msgs...)
+	}, p.rerty)
+}
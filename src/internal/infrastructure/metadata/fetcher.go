@@ -0,0 +1,124 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"shortener/src/internal/application/config"
+	shortlink "shortener/src/internal/domain/short_link"
+	"shortener/src/pkg/logger"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+type HTTPFetcher struct {
+	client      *http.Client
+	userAgent   string
+	maxBodySize int64
+}
+
+const maxRedirects = 5
+
+// sharedAddressSpace is the carrier-grade NAT range, which netip does not count as private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+func NewHTTPFetcher(cfg config.MetadataConfig) *HTTPFetcher {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateNetworks {
+		dialer.Control = rejectPrivateAddress
+	}
+
+	// No proxy from the environment: the address check has to see the target, not the proxy.
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   cfg.Timeout,
+		ResponseHeaderTimeout: cfg.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return &HTTPFetcher{
+		client: &http.Client{
+			Transport:     transport,
+			Timeout:       cfg.Timeout,
+			CheckRedirect: checkRedirect,
+		},
+		userAgent:   cfg.UserAgent,
+		maxBodySize: cfg.MaxBodySize,
+	}
+}
+
+// Fetch reads the head of the target page. Only the first maxBodySize bytes are read, so the metadata of a page
+// with a larger head is incomplete.
+func (f *HTTPFetcher) Fetch(ctx context.Context, targetURL string) (*shortlink.Metadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Error("failed to close response body", "err", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", shortlink.ErrMetadataUnavailable, resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("%w: content type %q", shortlink.ErrMetadataUnavailable, contentType)
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, f.maxBodySize), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", shortlink.ErrMetadataUnavailable, err)
+	}
+
+	metadata := parsePage(body, resp.Request.URL)
+	metadata.FetchedAt = time.Now().UTC()
+
+	return metadata, nil
+}
+
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("%w: stopped after %d redirects", shortlink.ErrMetadataUnavailable, maxRedirects)
+	}
+
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: redirect to %s scheme", shortlink.ErrMetadataUnavailable, req.URL.Scheme)
+	}
+
+	return nil
+}
+
+// rejectPrivateAddress runs after name resolution, so hosts resolving to internal addresses are refused too.
+func rejectPrivateAddress(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr := addrPort.Addr().Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || sharedAddressSpace.Contains(addr) {
+		return fmt.Errorf("%w: %s is not a public address", shortlink.ErrMetadataUnavailable, addr)
+	}
+
+	return nil
+}
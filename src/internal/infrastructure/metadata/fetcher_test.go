@@ -0,0 +1,163 @@
+package metadata_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"shortener/src/internal/application/config"
+	shortlink "shortener/src/internal/domain/short_link"
+	"shortener/src/internal/infrastructure/metadata"
+)
+
+func newFetcher(allowPrivate bool) *metadata.HTTPFetcher {
+	return metadata.NewHTTPFetcher(config.MetadataConfig{
+		Timeout:              time.Second,
+		MaxBodySize:          4 << 10,
+		UserAgent:            "ShortenerBot/test",
+		AllowPrivateNetworks: allowPrivate,
+	})
+}
+
+func serve(t *testing.T, contentType, body string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "ShortenerBot/test" {
+			t.Errorf("unexpected User-Agent %q", r.Header.Get("User-Agent"))
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write([]byte(body)) //nolint: errcheck // test server
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestHTTPFetcher_Fetch(t *testing.T) {
+	server := serve(t, "text/html; charset=utf-8", `<!DOCTYPE html>
+<html><head>
+<title>
+  Spring   sale
+</title>
+<meta name="description" content="Everything at half price">
+<meta property="og:title" content="Ignored, the page has a title">
+<meta property="og:image" content="/images/sale.png">
+<link rel="apple-touch-icon" href="/apple.png">
+<link rel="shortcut icon" href="static/icon.png">
+</head><body><title>not the title</title></body></html>`)
+
+	got, err := newFetcher(true).Fetch(context.Background(), server.URL+"/promo/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := shortlink.Metadata{
+		Title:       "Spring sale",
+		Description: "Everything at half price",
+		ImageURL:    server.URL + "/images/sale.png",
+		FaviconURL:  server.URL + "/promo/static/icon.png",
+	}
+	if got.FetchedAt.IsZero() {
+		t.Fatal("expected FetchedAt to be set")
+	}
+	got.FetchedAt = time.Time{}
+	if *got != want {
+		t.Fatalf("got %+v, want %+v", *got, want)
+	}
+}
+
+func TestHTTPFetcher_Fetch_OpenGraphFallbacks(t *testing.T) {
+	server := serve(t, "text/html", `<head>
+<base href="https://cdn.example.com/assets/">
+<meta property="og:title" content="Open Graph title">
+<meta property="og:description" content="Open Graph description">
+<meta property="og:image" content="javascript:alert(1)">
+</head>`)
+
+	got, err := newFetcher(true).Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Title != "Open Graph title" || got.Description != "Open Graph description" {
+		t.Fatalf("expected Open Graph title and description, got %+v", *got)
+	}
+	if got.ImageURL != "" {
+		t.Fatalf("expected a javascript: image to be dropped, got %q", got.ImageURL)
+	}
+	if got.FaviconURL != "https://cdn.example.com/favicon.ico" {
+		t.Fatalf("expected the default favicon resolved against <base>, got %q", got.FaviconURL)
+	}
+}
+
+func TestHTTPFetcher_Fetch_DecodesCharset(t *testing.T) {
+	// "Привет" in windows-1251.
+	server := serve(t, "text/html; charset=windows-1251", "<title>\xcf\xf0\xe8\xe2\xe5\xf2</title>")
+
+	got, err := newFetcher(true).Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Title != "Привет" {
+		t.Fatalf("got title %q, want %q", got.Title, "Привет")
+	}
+}
+
+func TestHTTPFetcher_Fetch_ReadsOnlyMaxBodySize(t *testing.T) {
+	server := serve(t, "text/html", "<head><!--"+strings.Repeat("x", 8<<10)+"--><title>Too far</title></head>")
+
+	got, err := newFetcher(true).Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Title != "" {
+		t.Fatalf("expected the title past the size limit to be ignored, got %q", got.Title)
+	}
+}
+
+func TestHTTPFetcher_Fetch_FollowsRedirects(t *testing.T) {
+	target := serve(t, "text/html", `<link rel="icon" href="/icon.svg">`)
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL+"/page", http.StatusFound))
+	t.Cleanup(redirect.Close)
+
+	got, err := newFetcher(true).Fetch(context.Background(), redirect.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.FaviconURL != target.URL+"/icon.svg" {
+		t.Fatalf("expected the favicon of the final page, got %q", got.FaviconURL)
+	}
+}
+
+func TestHTTPFetcher_Fetch_Unavailable(t *testing.T) {
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(notFound.Close)
+
+	tests := []struct {
+		name         string
+		url          string
+		allowPrivate bool
+	}{
+		{name: "not HTML", url: serve(t, "application/pdf", "%PDF-1.7").URL, allowPrivate: true},
+		{name: "error status", url: notFound.URL, allowPrivate: true},
+		{name: "private address", url: serve(t, "text/html", "<title>Internal</title>").URL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newFetcher(tt.allowPrivate).Fetch(context.Background(), tt.url)
+			if !errors.Is(err, shortlink.ErrMetadataUnavailable) {
+				t.Fatalf("expected ErrMetadataUnavailable, got: %v", err)
+			}
+		})
+	}
+}
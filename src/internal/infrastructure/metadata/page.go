@@ -0,0 +1,134 @@
+package metadata
+
+import (
+	"io"
+	"net/url"
+	shortlink "shortener/src/internal/domain/short_link"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	maxTitleLength       = 300
+	maxDescriptionLength = 1000
+	maxURLLength         = 2048
+)
+
+// parsePage reads the metadata from the head of the page at pageURL. Open Graph title and description are used
+// when the page has no <title> or description, and the favicon defaults to /favicon.ico.
+func parsePage(r io.Reader, pageURL *url.URL) *shortlink.Metadata {
+	var title, description, ogTitle, ogDescription, image, favicon string
+	base, baseSet := pageURL, false
+
+	tokenizer := html.NewTokenizer(r)
+head:
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+
+		token := tokenizer.Token()
+		if tokenType == html.EndTagToken && token.Data == "head" {
+			break
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue
+		}
+
+		switch token.Data {
+		case "body":
+			break head
+		case "base":
+			if href, err := pageURL.Parse(attr(token, "href")); err == nil && !baseSet {
+				base, baseSet = href, true
+			}
+		case "title":
+			if title == "" && tokenizer.Next() == html.TextToken {
+				title = string(tokenizer.Text())
+			}
+		case "meta":
+			content := attr(token, "content")
+			if strings.EqualFold(attr(token, "name"), "description") {
+				description = firstNonEmpty(description, content)
+			}
+			switch strings.ToLower(attr(token, "property")) {
+			case "og:title":
+				ogTitle = firstNonEmpty(ogTitle, content)
+			case "og:description":
+				ogDescription = firstNonEmpty(ogDescription, content)
+			case "og:image", "og:image:url", "og:image:secure_url":
+				image = firstNonEmpty(image, content)
+			}
+		case "link":
+			if slices.Contains(strings.Fields(strings.ToLower(attr(token, "rel"))), "icon") {
+				favicon = firstNonEmpty(favicon, attr(token, "href"))
+			}
+		}
+	}
+
+	if favicon == "" {
+		favicon = "/favicon.ico"
+	}
+
+	return &shortlink.Metadata{
+		Title:       truncate(collapseSpaces(firstNonEmpty(title, ogTitle)), maxTitleLength),
+		Description: truncate(collapseSpaces(firstNonEmpty(description, ogDescription)), maxDescriptionLength),
+		ImageURL:    resolveURL(base, image),
+		FaviconURL:  resolveURL(base, favicon),
+	}
+}
+
+// resolveURL returns an absolute http(s) URL for ref, or "" for other schemes and overlong URLs.
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+
+	resolved, err := base.Parse(ref)
+	if err != nil || resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+
+	if s := resolved.String(); len(s) <= maxURLLength {
+		return s
+	}
+
+	return ""
+}
+
+func attr(token html.Token, name string) string {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func truncate(s string, maxLength int) string {
+	if utf8.RuneCountInString(s) <= maxLength {
+		return s
+	}
+
+	return string([]rune(s)[:maxLength])
+}
@@ -0,0 +1,119 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	shortlink "shortener/src/internal/domain/short_link"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+var levels = map[string]qrcode.RecoveryLevel{
+	shortlink.QRLevelLow:      qrcode.Low,
+	shortlink.QRLevelMedium:   qrcode.Medium,
+	shortlink.QRLevelQuartile: qrcode.High,
+	shortlink.QRLevelHigh:     qrcode.Highest,
+}
+
+// Encoder draws QR codes itself from the module matrix, so the margin and colours are not limited to what the
+// qrcode package renders.
+type Encoder struct{}
+
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+func (e *Encoder) Encode(content string, options shortlink.QRCodeOptions) ([]byte, error) {
+	level, ok := levels[options.Level]
+	if !ok {
+		return nil, fmt.Errorf("unknown error correction level %q", options.Level)
+	}
+
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, err
+	}
+	code.DisableBorder = true
+	modules := code.Bitmap()
+
+	switch options.Format {
+	case shortlink.QRFormatPNG:
+		return encodePNG(modules, options)
+	case shortlink.QRFormatSVG:
+		return encodeSVG(modules, options), nil
+	default:
+		return nil, fmt.Errorf("unknown QR code format %q", options.Format)
+	}
+}
+
+// encodePNG scales modules by a whole number of pixels and centres the code, so the image is exactly
+// options.Size wide; the code never shrinks below one pixel per module.
+func encodePNG(modules [][]bool, options shortlink.QRCodeOptions) ([]byte, error) {
+	total := len(modules) + 2*options.Margin
+	scale := max(options.Size/total, 1)
+	size := max(options.Size, total)
+	offset := (size - total*scale) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{options.Background, options.Foreground})
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+
+			left := offset + (x+options.Margin)*scale
+			top := offset + (y+options.Margin)*scale
+			for py := top; py < top+scale; py++ {
+				for px := left; px < left+scale; px++ {
+					img.SetColorIndex(px, py, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeSVG draws one path in module units and lets the viewBox scale it to options.Size.
+func encodeSVG(modules [][]bool, options shortlink.QRCodeOptions) []byte {
+	total := len(modules) + 2*options.Margin
+
+	var path strings.Builder
+	for y, row := range modules {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", start+options.Margin, y+options.Margin, x-start, x-start)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" `+
+			`shape-rendering="crispEdges">`,
+		options.Size, options.Size, total, total,
+	)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, total, total, hexColor(options.Background))
+	fmt.Fprintf(&buf, `<path d="%s" fill="%s"/></svg>`, path.String(), hexColor(options.Foreground))
+
+	return buf.Bytes()
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
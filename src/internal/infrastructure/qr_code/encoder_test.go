@@ -0,0 +1,69 @@
+package qrcode_test
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	shortlink "shortener/src/internal/domain/short_link"
+	qrcode "shortener/src/internal/infrastructure/qr_code"
+)
+
+func options(format string) shortlink.QRCodeOptions {
+	return shortlink.QRCodeOptions{
+		Format:     format,
+		Size:       300,
+		Level:      shortlink.QRLevelMedium,
+		Margin:     2,
+		Foreground: color.RGBA{R: 0x11, G: 0x22, B: 0x33, A: 0xff},
+		Background: color.RGBA{R: 0xff, G: 0xee, B: 0xdd, A: 0xff},
+	}
+}
+
+func TestEncoder_PNG(t *testing.T) {
+	data, err := qrcode.NewEncoder().Encode("https://sho.rt/s/abc", options(shortlink.QRFormatPNG))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("not a PNG: %v", err)
+	}
+
+	if bounds := img.Bounds(); bounds.Dx() != 300 || bounds.Dy() != 300 {
+		t.Fatalf("got %dx%d image, want 300x300", bounds.Dx(), bounds.Dy())
+	}
+
+	// A version 2 code has 25 modules, 29 with the margin: 10 pixels each, centred with a 5 pixel offset.
+	corner := color.RGBAModel.Convert(img.At(5, 5)).(color.RGBA)
+	finder := color.RGBAModel.Convert(img.At(5+2*10, 5+2*10)).(color.RGBA)
+	if corner != options("").Background || finder != options("").Foreground {
+		t.Fatalf("got margin %v and finder pattern %v", corner, finder)
+	}
+}
+
+func TestEncoder_SVG(t *testing.T) {
+	data, err := qrcode.NewEncoder().Encode("https://sho.rt/s/abc", options(shortlink.QRFormatSVG))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svg := string(data)
+	for _, want := range []string{`width="300"`, `viewBox="0 0 29 29"`, `fill="#ffeedd"`, `fill="#112233"`, "M2 2h7"} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("svg has no %s: %s", want, svg)
+		}
+	}
+}
+
+func TestEncoder_UnknownLevel(t *testing.T) {
+	opts := options(shortlink.QRFormatPNG)
+	opts.Level = "X"
+
+	if _, err := qrcode.NewEncoder().Encode("https://sho.rt/s/abc", opts); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -1,39 +1,71 @@
 package generator
 
 import (
+	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"shortener/src/internal/application/config"
 	shortlink "shortener/src/internal/domain/short_link"
 )
 
-const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+const maxPolicyAttempts = 100
 
-var (
-	alphabetLen  = byte(len(alphabet))
-	maxRandValue = byte(255 - (256 % len(alphabet)))
-)
+type RandomShortLinkGenerator struct {
+	length       int
+	alphabet     string
+	maxRandValue byte
+	policy       shortlink.CodePolicy
+}
+
+func NewRandomShortCodeGenerator(cfg config.ShortCodeConfig, policy shortlink.CodePolicy) *RandomShortLinkGenerator {
+	return &RandomShortLinkGenerator{
+		length:       cfg.Length,
+		alphabet:     cfg.Alphabet,
+		maxRandValue: byte(255 - (256 % len(cfg.Alphabet))),
+		policy:       policy,
+	}
+}
 
-type RandomShortLinkGenerator struct{}
+func (g *RandomShortLinkGenerator) Generate(context.Context, string, int) (string, error) {
+	for range maxPolicyAttempts {
+		code, err := g.random()
+		if err != nil {
+			return "", err
+		}
+
+		err = g.policy.Check(code)
+		if err == nil {
+			return code, nil
+		}
+
+		if !errors.Is(err, shortlink.ErrShortCodeNotAllowed) {
+			return "", err
+		}
+	}
 
-func NewRandomShortCodeGenerator() *RandomShortLinkGenerator {
-	return &RandomShortLinkGenerator{}
+	return "", fmt.Errorf(
+		"no allowed short code after %d attempts: %w",
+		maxPolicyAttempts, shortlink.ErrShortCodeNotAllowed,
+	)
 }
 
-func (g *RandomShortLinkGenerator) Generate() (string, error) {
-	b := make([]byte, shortlink.ShortLinkLength)
+func (g *RandomShortLinkGenerator) random() (string, error) {
+	b := make([]byte, g.length)
+	alphabetLen := byte(len(g.alphabet))
 
-	for i := 0; i < shortlink.ShortLinkLength; {
+	for i := 0; i < g.length; {
 		var rb [1]byte
 
 		if _, err := rand.Read(rb[:]); err != nil {
 			return "", fmt.Errorf("read random: %w", err)
 		}
 
-		if rb[0] > maxRandValue {
+		if rb[0] > g.maxRandValue {
 			continue
 		}
 
-		b[i] = alphabet[rb[0]%alphabetLen]
+		b[i] = g.alphabet[rb[0]%alphabetLen]
 		i++
 	}
 
@@ -0,0 +1,44 @@
+package generator_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"shortener/src/internal/application/config"
+	shortlink "shortener/src/internal/domain/short_link"
+	generator "shortener/src/internal/infrastructure/short_link_generator"
+)
+
+type rejectPolicy string
+
+func (p rejectPolicy) Check(code string) error {
+	if strings.Contains(code, string(p)) {
+		return shortlink.ErrShortCodeNotAllowed
+	}
+	return nil
+}
+
+func TestRandomGenerator_SkipsCodesRejectedByPolicy(t *testing.T) {
+	gen := generator.NewRandomShortCodeGenerator(config.ShortCodeConfig{Length: 1, Alphabet: "AB"}, rejectPolicy("A"))
+
+	for range 50 {
+		code, err := gen.Generate(context.Background(), "", 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if code != "B" {
+			t.Fatalf("expected rejected codes to be skipped, got %q", code)
+		}
+	}
+}
+
+func TestRandomGenerator_GivesUpWhenEverythingIsRejected(t *testing.T) {
+	gen := generator.NewRandomShortCodeGenerator(config.ShortCodeConfig{Length: 2, Alphabet: "AB"}, rejectPolicy(""))
+
+	_, err := gen.Generate(context.Background(), "", 0)
+	if !errors.Is(err, shortlink.ErrShortCodeNotAllowed) {
+		t.Fatalf("expected ErrShortCodeNotAllowed, got %v", err)
+	}
+}
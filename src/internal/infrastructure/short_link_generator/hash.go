@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+	"shortener/src/internal/application/config"
+	shortlink "shortener/src/internal/domain/short_link"
+	"strconv"
+)
+
+// hashFallbackCodes is how many attempts hash the URL alone. Later attempts mix in random bytes, so
+// a popular URL shortened many times cannot use up its fixed fallback sequence.
+const hashFallbackCodes = 3
+
+// HashShortLinkGenerator derives codes from a keyed hash of the canonical target URL, so every
+// deployment sharing the key computes the same code for the same URL. Each retry attempt hashes
+// a different suffix, which gives a fixed sequence of fallback codes per URL.
+type HashShortLinkGenerator struct {
+	length   int
+	alphabet string
+	key      []byte
+}
+
+func NewHashShortCodeGenerator(cfg config.ShortCodeConfig) *HashShortLinkGenerator {
+	return &HashShortLinkGenerator{
+		length:   cfg.Length,
+		alphabet: cfg.Alphabet,
+		key:      []byte(cfg.Key),
+	}
+}
+
+func (g *HashShortLinkGenerator) Generate(_ context.Context, originalURL string, attempt int) (string, error) {
+	mac := hmac.New(sha512.New, g.key)
+	mac.Write([]byte(shortlink.CanonicalURL(originalURL, false)))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.Itoa(attempt)))
+
+	if attempt >= hashFallbackCodes {
+		var salt [16]byte
+		if _, err := rand.Read(salt[:]); err != nil {
+			return "", fmt.Errorf("read random: %w", err)
+		}
+		mac.Write(salt[:])
+	}
+
+	value := new(big.Int).SetBytes(mac.Sum(nil))
+	base := big.NewInt(int64(len(g.alphabet)))
+	digit := new(big.Int)
+
+	b := make([]byte, g.length)
+	for i := range b {
+		value.DivMod(value, base, digit)
+		b[i] = g.alphabet[digit.Int64()]
+	}
+
+	return string(b), nil
+}
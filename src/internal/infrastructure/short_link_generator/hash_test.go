@@ -0,0 +1,88 @@
+package generator_test
+
+import (
+	"context"
+	"testing"
+
+	"shortener/src/internal/application/config"
+	generator "shortener/src/internal/infrastructure/short_link_generator"
+)
+
+func hashConfig(key string) config.ShortCodeConfig {
+	return config.ShortCodeConfig{
+		Key:      key,
+		Length:   7,
+		Alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+	}
+}
+
+func TestHashGenerator_SameURLSameCode(t *testing.T) {
+	first := generator.NewHashShortCodeGenerator(hashConfig("secret"))
+	second := generator.NewHashShortCodeGenerator(hashConfig("secret"))
+
+	a, err := first.Generate(context.Background(), "HTTPS://Example.com:443", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := second.Generate(context.Background(), "https://example.com/", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a != b {
+		t.Fatalf("expected equivalent URLs to share a code, got %q and %q", a, b)
+	}
+	if len(a) != 7 {
+		t.Fatalf("expected code of length 7, got %q", a)
+	}
+}
+
+func TestHashGenerator_AttemptsGiveDistinctFallbacks(t *testing.T) {
+	gen := generator.NewHashShortCodeGenerator(hashConfig("secret"))
+
+	seen := make(map[string]bool)
+	for attempt := 0; attempt < 5; attempt++ {
+		code, err := gen.Generate(context.Background(), "https://example.com", attempt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if seen[code] {
+			t.Fatalf("attempt %d repeated code %q", attempt, code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestHashGenerator_DependsOnKey(t *testing.T) {
+	a, err := generator.NewHashShortCodeGenerator(hashConfig("first")).
+		Generate(context.Background(), "https://example.com", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := generator.NewHashShortCodeGenerator(hashConfig("second")).
+		Generate(context.Background(), "https://example.com", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a == b {
+		t.Fatalf("expected different keys to give different codes, both gave %q", a)
+	}
+}
+
+func TestHashGenerator_LaterAttemptsAreRandom(t *testing.T) {
+	gen := generator.NewHashShortCodeGenerator(hashConfig("secret"))
+
+	a, err := gen.Generate(context.Background(), "https://example.com", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := gen.Generate(context.Background(), "https://example.com", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a == b {
+		t.Fatalf("expected attempts past the fallback sequence to differ, both gave %q", a)
+	}
+}
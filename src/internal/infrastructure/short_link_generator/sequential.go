@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"shortener/src/internal/application/config"
+	"sync"
+)
+
+const (
+	feistelRounds = 4
+	// maxCodeSpace keeps the permutation domain and the reserved IDs within int64.
+	maxCodeSpace = uint64(1) << 62
+)
+
+var ErrCodeSpaceExhausted = errors.New("short code space exhausted")
+
+type BlockReserver interface {
+	// ReserveBlock atomically reserves size consecutive IDs and returns the first one.
+	ReserveBlock(ctx context.Context, size int64) (int64, error)
+}
+
+// SequentialShortLinkGenerator hands out IDs from reserved blocks and maps each ID to a code
+// through a keyed permutation, so codes never repeat but do not look sequential.
+type SequentialShortLinkGenerator struct {
+	reserver  BlockReserver
+	length    int
+	alphabet  string
+	blockSize int64
+	key       []byte
+
+	space    uint64
+	halfBits uint
+	halfMask uint64
+
+	mu   sync.Mutex
+	next int64
+	end  int64
+}
+
+func NewSequentialShortCodeGenerator(
+	reserver BlockReserver,
+	cfg config.ShortCodeConfig,
+) *SequentialShortLinkGenerator {
+	space := codeSpace(len(cfg.Alphabet), cfg.Length)
+	halfBits := uint(bits.Len64(space-1)+1) / 2
+
+	return &SequentialShortLinkGenerator{
+		reserver:  reserver,
+		length:    cfg.Length,
+		alphabet:  cfg.Alphabet,
+		blockSize: cfg.BlockSize,
+		key:       []byte(cfg.Key),
+		space:     space,
+		halfBits:  halfBits,
+		halfMask:  uint64(1)<<halfBits - 1,
+	}
+}
+
+func (g *SequentialShortLinkGenerator) Generate(ctx context.Context, _ string, _ int) (string, error) {
+	id, err := g.nextID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if uint64(id) >= g.space {
+		return "", ErrCodeSpaceExhausted
+	}
+
+	return g.encode(g.permute(uint64(id))), nil
+}
+
+func (g *SequentialShortLinkGenerator) nextID(ctx context.Context) (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.next >= g.end {
+		start, err := g.reserver.ReserveBlock(ctx, g.blockSize)
+		if err != nil {
+			return 0, fmt.Errorf("reserve short code block: %w", err)
+		}
+
+		g.next, g.end = start, start+g.blockSize
+	}
+
+	id := g.next
+	g.next++
+
+	return id, nil
+}
+
+// permute is a balanced Feistel network over halfBits*2 bits. Values that fall outside
+// the code space are walked through the permutation again until they land inside it,
+// which keeps the mapping a bijection on [0, space).
+func (g *SequentialShortLinkGenerator) permute(value uint64) uint64 {
+	for {
+		left, right := value>>g.halfBits, value&g.halfMask
+		for round := range feistelRounds {
+			left, right = right, left^(g.roundFunc(round, right)&g.halfMask)
+		}
+
+		value = left<<g.halfBits | right
+		if value < g.space {
+			return value
+		}
+	}
+}
+
+func (g *SequentialShortLinkGenerator) roundFunc(round int, value uint64) uint64 {
+	var msg [9]byte
+	msg[0] = byte(round)
+	binary.BigEndian.PutUint64(msg[1:], value)
+
+	mac := hmac.New(sha256.New, g.key)
+	mac.Write(msg[:])
+
+	return binary.BigEndian.Uint64(mac.Sum(nil))
+}
+
+func (g *SequentialShortLinkGenerator) encode(value uint64) string {
+	base := uint64(len(g.alphabet))
+	b := make([]byte, g.length)
+	for i := g.length - 1; i >= 0; i-- {
+		b[i] = g.alphabet[value%base]
+		value /= base
+	}
+
+	return string(b)
+}
+
+func codeSpace(base, length int) uint64 {
+	space := uint64(1)
+	for range length {
+		if space > math.MaxUint64/uint64(base) {
+			return maxCodeSpace
+		}
+		space *= uint64(base)
+	}
+
+	return min(space, maxCodeSpace)
+}
@@ -0,0 +1,105 @@
+package generator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"shortener/src/internal/application/config"
+	generator "shortener/src/internal/infrastructure/short_link_generator"
+)
+
+type counterReserver struct {
+	next  int64
+	calls int
+}
+
+func (r *counterReserver) ReserveBlock(_ context.Context, size int64) (int64, error) {
+	r.calls++
+	start := r.next
+	r.next += size
+	return start, nil
+}
+
+func TestSequentialGenerator_CoversWholeSpaceWithoutRepeats(t *testing.T) {
+	reserver := &counterReserver{}
+	gen := generator.NewSequentialShortCodeGenerator(reserver, config.ShortCodeConfig{
+		Key:       "secret",
+		BlockSize: 10,
+		Length:    3,
+		Alphabet:  "abcde",
+	})
+
+	seen := make(map[string]bool)
+	for i := 0; i < 125; i++ {
+		code, err := gen.Generate(context.Background(), "", 0)
+		if err != nil {
+			t.Fatalf("unexpected error at %d: %v", i, err)
+		}
+		if len(code) != 3 {
+			t.Fatalf("expected code of length 3, got %q", code)
+		}
+		if seen[code] {
+			t.Fatalf("code %q generated twice", code)
+		}
+		seen[code] = true
+	}
+
+	if reserver.calls != 13 {
+		t.Fatalf("expected 13 block reservations, got %d", reserver.calls)
+	}
+
+	if _, err := gen.Generate(context.Background(), "", 0); !errors.Is(err, generator.ErrCodeSpaceExhausted) {
+		t.Fatalf("expected ErrCodeSpaceExhausted, got %v", err)
+	}
+}
+
+func TestSequentialGenerator_DependsOnKey(t *testing.T) {
+	cfg := config.ShortCodeConfig{
+		Key:       "first",
+		BlockSize: 100,
+		Length:    6,
+		Alphabet:  "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+	}
+	first := generator.NewSequentialShortCodeGenerator(&counterReserver{}, cfg)
+	cfg.Key = "second"
+	second := generator.NewSequentialShortCodeGenerator(&counterReserver{}, cfg)
+
+	same := 0
+	for i := 0; i < 20; i++ {
+		a, err := first.Generate(context.Background(), "", 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b, err := second.Generate(context.Background(), "", 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if a == b {
+			same++
+		}
+	}
+
+	if same > 1 {
+		t.Fatalf("expected different keys to produce different codes, %d of 20 matched", same)
+	}
+}
+
+func TestSequentialGenerator_ReserveError(t *testing.T) {
+	gen := generator.NewSequentialShortCodeGenerator(failingReserver{}, config.ShortCodeConfig{
+		Key:       "secret",
+		BlockSize: 10,
+		Length:    6,
+		Alphabet:  "abc",
+	})
+
+	if _, err := gen.Generate(context.Background(), "", 0); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+type failingReserver struct{}
+
+func (failingReserver) ReserveBlock(context.Context, int64) (int64, error) {
+	return 0, errors.New("db down")
+}
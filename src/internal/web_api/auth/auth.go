@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	apikey "shortener/src/internal/domain/api_key"
+	"shortener/src/pkg/logger"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type apiKeyContextKey struct{}
+
+type adminContextKey struct{}
+
+const bearerPrefix = "Bearer "
+
+func Authenticate(apiKeyService apikey.APIKeyService, adminToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if isAdminToken(token, adminToken) {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), adminContextKey{}, true)))
+				return
+			}
+
+			key, err := apiKeyService.Authenticate(r.Context(), token)
+			if err != nil {
+				if errors.Is(err, apikey.ErrInvalidAPIKey) {
+					unauthorized(w, err.Error())
+					return
+				}
+
+				logger.Error("failed to authenticate api key", "err", err)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+		})
+	}
+}
+
+func RequireAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if APIKey(r.Context()) == nil {
+			unauthorized(w, "api key required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func RequireAdmin(adminToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if adminToken == "" {
+				http.Error(w, "admin api is disabled", http.StatusForbidden)
+				return
+			}
+
+			token, ok := bearerToken(r)
+			if !ok || !isAdminToken(token, adminToken) {
+				unauthorized(w, "admin token required")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func APIKey(ctx context.Context) *apikey.APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*apikey.APIKey) //nolint: errcheck // missing key means anonymous
+	return key
+}
+
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminContextKey{}).(bool) //nolint: errcheck // missing flag means not admin
+	return admin
+}
+
+func OwnerID(ctx context.Context) *uuid.UUID {
+	key := APIKey(ctx)
+	if key == nil {
+		return nil
+	}
+
+	return &key.OwnerID
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+
+	return strings.TrimSpace(header[len(bearerPrefix):]), true
+}
+
+func isAdminToken(token, adminToken string) bool {
+	return adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
+
+func unauthorized(w http.ResponseWriter, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="shortener"`)
+	http.Error(w, msg, http.StatusUnauthorized)
+}
@@ -2,24 +2,33 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	shortlink "shortener/src/internal/domain/short_link"
 	"shortener/src/internal/domain/visit"
+	"shortener/src/internal/web_api/auth"
 	"shortener/src/pkg/logger"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type AnalyticsController struct {
-	visitService visit.VisitService
+	shortLinkService shortlink.ShortLinkService
+	visitService     visit.VisitService
 }
 
-func NewAnalyticsController(visitService visit.VisitService) *AnalyticsController {
+func NewAnalyticsController(
+	shortLinkService shortlink.ShortLinkService,
+	visitService visit.VisitService,
+) *AnalyticsController {
 	return &AnalyticsController{
-		visitService: visitService,
+		shortLinkService: shortLinkService,
+		visitService:     visitService,
 	}
 }
 
 func (c *AnalyticsController) UseHandlers(r chi.Router) {
+	r.Get("/analytics", c.TagAnalytics)
 	r.Get("/analytics/{short_url}", c.Analytics)
 }
 
@@ -27,21 +36,45 @@ func (c *AnalyticsController) UseHandlers(r chi.Router) {
 //
 //	@Summary		Получить аналитику по короткой ссылке
 //	@Description	Возвращает статистику переходов, агрегированную по дням, месяцам или User-Agent.
+//	@Description	Группировка budget возвращает лимит переходов и его остаток.
+//	@Description	Группировка variant сравнивает число переходов и их долю по вариантам A/B-теста.
+//	@Description	Группировка source разделяет сканирования QR-кода (qr) и обычные переходы (direct).
+//	@Description	Аналитика ссылки с владельцем доступна только по API-ключу владельца.
 //	@Tags			analytics
 //	@Param			short_url	path		string		true	"Короткий код"
-//	@Param			group		query		string		true	"Тип группировки"	Enums(day,month,userAgent)
+//	@Param			domain		query		string		false	"Брендированный домен ссылки"
+//	@Param			group		query		string		true	"Тип группировки"	Enums(day,month,userAgent,budget,variant,source)
 //	@Success		200			{object}	interface{}	"Результат зависит от типа группировки"
 //	@Failure		400			{string}	string		"unknown group"
+//	@Failure		401			{string}	string		"invalid api key"
+//	@Failure		403			{string}	string		"short link belongs to another owner"
+//	@Failure		404			{string}	string		"short link not found"
 //	@Failure		500			{string}	string		"internal error"
+//	@Security		BearerAuth
 //	@Router			/analytics/{short_url} [get]
 func (c *AnalyticsController) Analytics(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	shortURL := chi.URLParam(r, "short_url")
 	group := r.URL.Query().Get("group")
 
+	shortLink, err := c.shortLinkService.Owned(ctx, auth.OwnerID(ctx), linkDomain(r), shortURL)
+	if err != nil {
+		switch {
+		case errors.Is(err, shortlink.ErrShortLinkNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, shortlink.ErrForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			logger.Error("failed to get short link", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
 	switch group {
 	case "day":
-		res, err := c.visitService.ByDayAnalytics(ctx, shortURL)
+		res, err := c.visitService.ByDayAnalytics(ctx, shortLink.ID)
 		if err != nil {
 			logger.Error("failed to get analytics", "err", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -54,7 +87,7 @@ func (c *AnalyticsController) Analytics(w http.ResponseWriter, r *http.Request)
 		}
 
 	case "month":
-		res, err := c.visitService.ByMonthAnalytics(ctx, shortURL)
+		res, err := c.visitService.ByMonthAnalytics(ctx, shortLink.ID)
 		if err != nil {
 			logger.Error("failed to get analytics", "err", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -67,7 +100,7 @@ func (c *AnalyticsController) Analytics(w http.ResponseWriter, r *http.Request)
 		}
 
 	case "userAgent":
-		res, err := c.visitService.ByUserAgentAnalytics(ctx, shortURL)
+		res, err := c.visitService.ByUserAgentAnalytics(ctx, shortLink.ID)
 		if err != nil {
 			logger.Error("failed to get analytics", "err", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -79,8 +112,118 @@ func (c *AnalyticsController) Analytics(w http.ResponseWriter, r *http.Request)
 			logger.Error("failed to write response", "err", err)
 		}
 
+	case "variant":
+		variants := make([]string, len(shortLink.Variants))
+		for i, variant := range shortLink.Variants {
+			variants[i] = variant.Name
+		}
+
+		res, err := c.visitService.ByVariantAnalytics(ctx, shortLink.ID, variants)
+		if err != nil {
+			logger.Error("failed to get analytics", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			logger.Error("failed to write response", "err", err)
+		}
+
+	case "source":
+		res, err := c.visitService.BySourceAnalytics(ctx, shortLink.ID)
+		if err != nil {
+			logger.Error("failed to get analytics", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			logger.Error("failed to write response", "err", err)
+		}
+
+	case "budget":
+		res := shortLink.ClickBudget()
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			logger.Error("failed to write response", "err", err)
+		}
+
 	default:
 		logger.Error("unknown group", "group", group)
 		http.Error(w, "unknown group", http.StatusBadRequest)
 	}
 }
+
+// TagAnalytics godoc
+//
+//	@Summary		Получить аналитику по тегу
+//	@Description	Возвращает статистику переходов по всем неудалённым ссылкам с тегом,
+//	@Description	агрегированную по дням, месяцам, User-Agent или источнику.
+//	@Description	По API-ключу учитываются только ссылки его владельца; с токеном администратора — ссылки всех владельцев.
+//	@Tags			analytics
+//	@Param			tag		query		string		true	"Тег"
+//	@Param			group	query		string		true	"Тип группировки"	Enums(day,month,userAgent,source)
+//	@Success		200		{object}	interface{}	"Результат зависит от типа группировки"
+//	@Failure		400		{string}	string		"unknown group"
+//	@Failure		401		{string}	string		"api key required"
+//	@Failure		500		{string}	string		"internal error"
+//	@Security		BearerAuth
+//	@Router			/analytics [get]
+func (c *AnalyticsController) TagAnalytics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tag := r.URL.Query().Get("tag")
+	group := r.URL.Query().Get("group")
+
+	if tag == "" {
+		http.Error(w, "tag is required", http.StatusBadRequest)
+		return
+	}
+
+	ownerID := auth.OwnerID(ctx)
+	if ownerID == nil && !auth.IsAdmin(ctx) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="shortener"`)
+		http.Error(w, "api key required", http.StatusUnauthorized)
+		return
+	}
+
+	linkIDs, err := c.shortLinkService.LinkIDsByTag(ctx, ownerID, tag)
+	if err != nil {
+		if errors.Is(err, shortlink.ErrInvalidTag) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger.Error("failed to get links by tag", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var res any
+	switch group {
+	case "day":
+		res, err = c.visitService.ByDayAnalytics(ctx, linkIDs...)
+	case "month":
+		res, err = c.visitService.ByMonthAnalytics(ctx, linkIDs...)
+	case "userAgent":
+		res, err = c.visitService.ByUserAgentAnalytics(ctx, linkIDs...)
+	case "source":
+		res, err = c.visitService.BySourceAnalytics(ctx, linkIDs...)
+	default:
+		http.Error(w, "unknown group", http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		logger.Error("failed to get analytics", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Error("failed to write response", "err", err)
+	}
+}
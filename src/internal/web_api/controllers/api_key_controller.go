@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	apikey "shortener/src/internal/domain/api_key"
+	"shortener/src/internal/web_api/auth"
+	"shortener/src/internal/web_api/models"
+	"shortener/src/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type APIKeyController struct {
+	apiKeyService apikey.APIKeyService
+	validator     *validator.Validate
+	adminToken    string
+}
+
+func NewAPIKeyController(
+	apiKeyService apikey.APIKeyService,
+	validator *validator.Validate,
+	adminToken string,
+) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService: apiKeyService,
+		validator:     validator,
+		adminToken:    adminToken,
+	}
+}
+
+func (c *APIKeyController) UseHandlers(r chi.Router) {
+	r.Route("/api-keys", func(r chi.Router) {
+		r.Use(auth.RequireAdmin(c.adminToken))
+		r.Post("/", c.Create)
+		r.Delete("/{id}", c.Revoke)
+	})
+}
+
+// Create godoc
+//
+//	@Summary		Выпустить API-ключ
+//	@Description	Создаёт API-ключ. Ключ возвращается только в этом ответе, в базе хранится его хэш.
+//	@Description	Если ownerID не задан, создаётся новый владелец. Требует ADMIN_TOKEN.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.CreateAPIKeyRequest	true	"Параметры ключа"
+//	@Success		201		{object}	models.CreateAPIKeyResponse
+//	@Failure		400		{string}	string	"bad request"
+//	@Failure		401		{string}	string	"admin token required"
+//	@Failure		403		{string}	string	"admin api is disabled"
+//	@Failure		500		{string}	string	"internal error"
+//	@Security		BearerAuth
+//	@Router			/api-keys [post]
+func (c *APIKeyController) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := c.validator.StructCtx(ctx, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key, token, err := c.apiKeyService.Issue(ctx, req.OwnerID, req.Name)
+	if err != nil {
+		logger.Error("failed to issue api key", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res := models.APIKeyToCreateResponse(*key, token)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Error("failed to write response", "err", err)
+	}
+}
+
+// Revoke godoc
+//
+//	@Summary		Отозвать API-ключ
+//	@Description	Отзывает API-ключ. Требует ADMIN_TOKEN.
+//	@Tags			api-keys
+//	@Param			id	path	string	true	"ID ключа"
+//	@Success		204	"No Content"
+//	@Failure		400	{string}	string	"bad request"
+//	@Failure		401	{string}	string	"admin token required"
+//	@Failure		403	{string}	string	"admin api is disabled"
+//	@Failure		404	{string}	string	"api key not found"
+//	@Failure		500	{string}	string	"internal error"
+//	@Security		BearerAuth
+//	@Router			/api-keys/{id} [delete]
+func (c *APIKeyController) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := c.apiKeyService.Revoke(ctx, id); err != nil {
+		if errors.Is(err, apikey.ErrAPIKeyNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		logger.Error("failed to revoke api key", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	brandeddomain "shortener/src/internal/domain/branded_domain"
+	"shortener/src/internal/web_api/auth"
+	"shortener/src/internal/web_api/models"
+	"shortener/src/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type DomainController struct {
+	domainService brandeddomain.DomainService
+	validator     *validator.Validate
+	adminToken    string
+}
+
+func NewDomainController(
+	domainService brandeddomain.DomainService,
+	validator *validator.Validate,
+	adminToken string,
+) *DomainController {
+	return &DomainController{
+		domainService: domainService,
+		validator:     validator,
+		adminToken:    adminToken,
+	}
+}
+
+func (c *DomainController) UseHandlers(r chi.Router) {
+	r.Route("/domains", func(r chi.Router) {
+		r.Use(auth.RequireAdmin(c.adminToken))
+		r.Post("/", c.Create)
+		r.Get("/", c.List)
+		r.Delete("/{host}", c.Delete)
+	})
+}
+
+// Create godoc
+//
+//	@Summary		Зарегистрировать брендированный домен
+//	@Description	Добавляет домен, с которого обслуживаются короткие ссылки. DNS домена должен указывать на сервис.
+//	@Description	Требует ADMIN_TOKEN.
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.CreateDomainRequest	true	"Домен"
+//	@Success		201		{object}	models.DomainResponse
+//	@Failure		400		{string}	string	"bad request"
+//	@Failure		401		{string}	string	"admin token required"
+//	@Failure		403		{string}	string	"admin api is disabled"
+//	@Failure		409		{string}	string	"domain already exists"
+//	@Failure		500		{string}	string	"internal error"
+//	@Security		BearerAuth
+//	@Router			/domains [post]
+func (c *DomainController) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req models.CreateDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := c.validator.StructCtx(ctx, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	domain, err := c.domainService.Register(ctx, req.Host)
+	if err != nil {
+		if errors.Is(err, brandeddomain.ErrDomainAlreadyExists) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		logger.Error("failed to register domain", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res := models.DomainToResponse(*domain)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Error("failed to write response", "err", err)
+	}
+}
+
+// List godoc
+//
+//	@Summary		Список брендированных доменов
+//	@Description	Требует ADMIN_TOKEN.
+//	@Tags			domains
+//	@Produce		json
+//	@Success		200	{array}		models.DomainResponse
+//	@Failure		401	{string}	string	"admin token required"
+//	@Failure		403	{string}	string	"admin api is disabled"
+//	@Failure		500	{string}	string	"internal error"
+//	@Security		BearerAuth
+//	@Router			/domains [get]
+func (c *DomainController) List(w http.ResponseWriter, r *http.Request) {
+	domains, err := c.domainService.List(r.Context())
+	if err != nil {
+		logger.Error("failed to list domains", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]models.DomainResponse, 0, len(domains))
+	for _, domain := range domains {
+		res = append(res, models.DomainToResponse(domain))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Error("failed to write response", "err", err)
+	}
+}
+
+// Delete godoc
+//
+//	@Summary		Удалить брендированный домен
+//	@Description	Удаляет домен, если на нём нет коротких ссылок. Требует ADMIN_TOKEN.
+//	@Tags			domains
+//	@Param			host	path	string	true	"Домен"
+//	@Success		204		"No Content"
+//	@Failure		401		{string}	string	"admin token required"
+//	@Failure		403		{string}	string	"admin api is disabled"
+//	@Failure		404		{string}	string	"domain not found"
+//	@Failure		409		{string}	string	"domain has short links"
+//	@Failure		500		{string}	string	"internal error"
+//	@Security		BearerAuth
+//	@Router			/domains/{host} [delete]
+func (c *DomainController) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := c.domainService.Delete(r.Context(), chi.URLParam(r, "host")); err != nil {
+		switch {
+		case errors.Is(err, brandeddomain.ErrDomainNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, brandeddomain.ErrDomainInUse):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			logger.Error("failed to delete domain", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
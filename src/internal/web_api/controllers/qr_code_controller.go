@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	brandeddomain "shortener/src/internal/domain/branded_domain"
+	shortlink "shortener/src/internal/domain/short_link"
+	"shortener/src/internal/web_api/models"
+	"shortener/src/pkg/logger"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var qrCodeContentTypes = map[string]string{
+	shortlink.QRFormatPNG: "image/png",
+	shortlink.QRFormatSVG: "image/svg+xml",
+}
+
+type QRCodeController struct {
+	shortLinkService shortlink.ShortLinkService
+	domainService    brandeddomain.DomainService
+	qrCodeService    shortlink.QRCodeService
+	cacheTTL         time.Duration
+	passthrough      http.HandlerFunc
+}
+
+func NewQRCodeController(
+	shortLinkService shortlink.ShortLinkService,
+	domainService brandeddomain.DomainService,
+	qrCodeService shortlink.QRCodeService,
+	cacheTTL time.Duration,
+	passthrough http.HandlerFunc,
+) *QRCodeController {
+	return &QRCodeController{
+		shortLinkService: shortLinkService,
+		domainService:    domainService,
+		qrCodeService:    qrCodeService,
+		cacheTTL:         cacheTTL,
+		passthrough:      passthrough,
+	}
+}
+
+func (c *QRCodeController) UseHandlers(r chi.Router) {
+	r.Get("/s/{short_url}/qr", c.QRCode)
+}
+
+// QRCode godoc
+//
+//	@Summary		Получить QR-код короткой ссылки
+//	@Description	Возвращает QR-код полного короткого URL в PNG или SVG. В QR-код попадает метка ?src=qr: при переходе
+//	@Description	она удаляется, а визит сохраняется с источником qr (группировка source в статистике).
+//	@Description	Для ссылки с pathPassthrough=true путь /qr передаётся в целевой URL, как любой другой путь.
+//	@Tags			shortlink
+//	@Produce		png
+//	@Produce		image/svg+xml
+//	@Param			short_url	path		string	true	"Короткий код"
+//	@Param			format		query		string	false	"Формат, по умолчанию png"	Enums(png,svg)
+//	@Param			size		query		int		false	"Ширина в пикселях, от 64 до 2048, по умолчанию 256"
+//	@Param			level		query		string	false	"Уровень коррекции ошибок, по умолчанию M"	Enums(L,M,Q,H)
+//	@Param			margin		query		int		false	"Поле в модулях, от 0 до 16, по умолчанию 4"
+//	@Param			fg			query		string	false	"Цвет модулей RRGGBB, по умолчанию 000000"
+//	@Param			bg			query		string	false	"Цвет фона RRGGBB, по умолчанию ffffff"
+//	@Success		200			{file}		file	"QR-код"
+//	@Failure		400			{string}	string	"invalid options"
+//	@Failure		404			{string}	string	"short link not found"
+//	@Failure		410			{string}	string	"short link deleted, expired or click limit reached"
+//	@Failure		500			{string}	string	"internal error"
+//	@Router			/s/{short_url}/qr [get]
+func (c *QRCodeController) QRCode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortURL := chi.URLParam(r, "short_url")
+
+	domain, err := c.domainService.Resolve(ctx, r.Host)
+	if err != nil {
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	// chi prefers this route to /s/{short_url}/*, so a link that passes its path through gets /qr back here.
+	shortLink, err := c.shortLinkService.Get(ctx, domain, shortURL)
+	if shortLink != nil && shortLink.PathPassthrough {
+		c.passthrough(w, r)
+		return
+	}
+
+	if err != nil {
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	options, err := models.ParseQRCodeOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	content := fmt.Sprintf("%s://%s/s/%s?%s", requestScheme(r), r.Host, shortLink.ShortCode, shortlink.QRSourceMarker)
+	image, err := c.qrCodeService.Render(ctx, content, options)
+	if err != nil {
+		logger.Error("failed to render qr code", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", qrCodeContentTypes[options.Format])
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(c.cacheTTL.Seconds())))
+	if _, err := w.Write(image); err != nil {
+		logger.Error("failed to write qr code", "err", err)
+	}
+}
+
+// requestScheme is the scheme the client used, as reported by a TLS-terminating proxy in front of the service.
+func requestScheme(r *http.Request) string {
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		return "https"
+	}
+
+	return "http"
+}
@@ -1,13 +1,25 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math/rand/v2"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
+	brandeddomain "shortener/src/internal/domain/branded_domain"
 	shortlink "shortener/src/internal/domain/short_link"
 	"shortener/src/internal/domain/visit"
+	"shortener/src/internal/web_api/auth"
 	"shortener/src/internal/web_api/models"
+	"shortener/src/internal/web_api/public"
+	"shortener/src/internal/web_api/validation"
 	"shortener/src/pkg/logger"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -16,68 +28,318 @@ import (
 )
 
 type ShortLinkController struct {
-	shortLinkService shortlink.ShortLinkService
-	visitService     visit.VisitService
-	validator        *validator.Validate
+	shortLinkService     shortlink.ShortLinkService
+	domainService        brandeddomain.DomainService
+	unlockService        shortlink.UnlockService
+	handoffService       shortlink.HandoffService
+	urlPolicy            shortlink.URLPolicy
+	visitService         visit.VisitService
+	metadataService      shortlink.MetadataService
+	countryResolver      visit.CountryResolver
+	validator            *validator.Validate
+	allowAnonymousCreate bool
 }
 
+const (
+	unlockCookiePrefix  = "unlock_"
+	variantCookiePrefix = "variant_"
+	previewCookiePrefix = "preview_"
+	maxUnlockFormSize   = 4 << 10
+	maxHandoffFormSize  = 1 << 10
+	maxBatchItems       = 10000
+	maxBatchBodySize    = 16 << 20
+
+	permanentRedirectMaxAge = 24 * time.Hour
+	variantCookieMaxAge     = 30 * 24 * time.Hour
+	previewCookieMaxAge     = 10 * time.Minute
+)
+
 func NewShortLinkController(
 	shortLinkService shortlink.ShortLinkService,
+	domainService brandeddomain.DomainService,
+	unlockService shortlink.UnlockService,
+	handoffService shortlink.HandoffService,
+	urlPolicy shortlink.URLPolicy,
 	visitService visit.VisitService,
+	metadataService shortlink.MetadataService,
+	countryResolver visit.CountryResolver,
 	validator *validator.Validate,
+	allowAnonymousCreate bool,
 ) *ShortLinkController {
 	return &ShortLinkController{
-		shortLinkService: shortLinkService,
-		visitService:     visitService,
-		validator:        validator,
+		shortLinkService:     shortLinkService,
+		domainService:        domainService,
+		unlockService:        unlockService,
+		handoffService:       handoffService,
+		urlPolicy:            urlPolicy,
+		visitService:         visitService,
+		metadataService:      metadataService,
+		countryResolver:      countryResolver,
+		validator:            validator,
+		allowAnonymousCreate: allowAnonymousCreate,
 	}
 }
 
 func (c *ShortLinkController) UseHandlers(r chi.Router) {
 	r.Post("/shorten", c.Create)
+	r.Post("/shorten/batch", c.CreateBatch)
 	r.Get("/s/{short_url}", c.Redirect)
+	r.Head("/s/{short_url}", c.Redirect)
+	r.Post("/s/{short_url}", c.Unlock)
+	r.Get("/s/{short_url}/*", c.Redirect)
+	r.Head("/s/{short_url}/*", c.Redirect)
+	r.Post("/s/{short_url}/*", c.Unlock)
+	r.Post("/handoff/{short_url}", c.Handoff)
+	r.Get("/links", c.List)
+	r.With(auth.RequireAPIKey).Patch("/links/{short_url}", c.Update)
+	r.With(auth.RequireAPIKey).Delete("/links/{short_url}", c.Delete)
+	r.With(auth.RequireAPIKey).Post("/links/{short_url}/tags", c.AttachTags)
+	r.With(auth.RequireAPIKey).Delete("/links/{short_url}/tags/{tag}", c.DetachTag)
 }
 
 // Create godoc
 //
 //	@Summary		Создать короткую ссылку
 //	@Description	Создаёт новую короткую ссылку. Если shortCode не задан, он генерируется.
+//	@Description	Ссылка, созданная по API-ключу, принадлежит его владельцу. Без ключа создание доступно,
+//	@Description	только если включено ALLOW_ANONYMOUS_CREATE.
+//	@Description	С dedupe=true возвращает уже существующую ссылку владельца на тот же URL с кодом 200.
+//	@Description	dedupe требует API-ключ. Истёкшая или исчерпанная ссылка заменяется новой, а действующая
+//	@Description	с другими expiresAt, maxClicks, fallbackURL или паролем даёт 409.
+//	@Description	domain задаёт зарегистрированный брендированный домен; код уникален в пределах домена.
+//	@Description	Если свой shortURL занят, ответ 409 содержит свободные варианты в suggestions.
+//	@Description	originalURL и fallbackURL проверяются политикой URL: схема, блок- и allow-листы доменов,
+//	@Description	другие сокращатели и ссылки на сам сервис; нарушение даёт 422.
+//	@Description	Свой код из списка зарезервированных слов или с нецензурным словом отклоняется с кодом 422.
+//	@Description	Заголовок, описание, картинка и иконка целевой страницы загружаются в фоне и появляются в поле metadata.
 //	@Tags			shortlink
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		models.CreateShortLinkRequest	true	"Данные для создания короткой ссылки"
-//	@Success		201		{object}	models.CreateShortLinkResponse
-//	@Failure		400		{string}	string	"bad request"
-//	@Failure		409		{string}	string	"short link already exists"
-//	@Failure		500		{string}	string	"internal error"
+//	@Success		200		{object}	models.ShortLinkResponse		"existing link (dedupe)"
+//	@Success		201		{object}	models.ShortLinkResponse
+//	@Failure		400		{string}	string								"bad request"
+//	@Failure		401		{string}	string								"api key required"
+//	@Failure		409		{object}	models.ShortLinkConflictResponse	"short link already exists or dedupe options differ"
+//	@Failure		422		{string}	string								"short code or target URL is not allowed"
+//	@Failure		500		{string}	string								"internal error"
+//	@Security		BearerAuth
 //	@Router			/shorten [post]
 func (c *ShortLinkController) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	ownerID := auth.OwnerID(ctx)
+	if ownerID == nil && !c.allowAnonymousCreate {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="shortener"`)
+		http.Error(w, "api key required", http.StatusUnauthorized)
+		return
+	}
+
 	var req models.CreateShortLinkRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if err := c.validator.StructCtx(ctx, req); err != nil {
+	err := c.validator.StructCtx(ctx, req)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	shortLink, err := c.shortLinkService.Create(ctx, req.ShortURLString(), req.OriginalURL)
+	params := req.ToCreateParams()
+	params.OwnerID = ownerID
+
+	params.Domain, err = c.createDomain(r, req)
+	if err != nil {
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	var shortLink *shortlink.ShortLink
+	status := http.StatusCreated
+	if req.Dedupe {
+		var created bool
+		shortLink, created, err = c.shortLinkService.GetOrCreate(ctx, params)
+		if !created {
+			status = http.StatusOK
+		}
+	} else {
+		shortLink, err = c.shortLinkService.Create(ctx, params)
+	}
+
 	if err != nil {
 		if errors.Is(err, shortlink.ErrShortLinkAlreadyExists) {
+			if req.ShortURL != nil {
+				c.writeAliasConflict(w, r, params.Domain, *req.ShortURL, err)
+				return
+			}
+
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		if errors.Is(err, shortlink.ErrDedupeOptionsMismatch) || errors.Is(err, shortlink.ErrTargetAlreadyShortened) {
 			http.Error(w, err.Error(), http.StatusConflict)
 			return
 		}
 
+		if errors.Is(err, shortlink.ErrDedupeRequiresOwner) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="shortener"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if errors.Is(err, shortlink.ErrShortCodeNotAllowed) || errors.Is(err, shortlink.ErrTargetNotAllowed) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
 		logger.Error("failed to create short link", "err", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if status == http.StatusCreated {
+		c.requestMetadata(ctx, *shortLink)
 	}
 
-	res := models.ShortLinkToCreateResponse(*shortLink)
+	res := models.ShortLinkToResponse(*shortLink)
 
-	w.WriteHeader(http.StatusCreated)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Error("failed to write response", "err", err)
+	}
+}
+
+// CreateBatch godoc
+//
+//	@Summary		Создать короткие ссылки пакетом
+//	@Description	Принимает JSON-массив CreateShortLinkRequest или CSV (Content-Type: text/csv) с заголовком
+//	@Description	originalURL,domain,shortURL,expiresAt,fallbackURL,maxClicks,password,redirectCode,queryPassthrough,
+//	@Description	pathPassthrough,title,description.
+//	@Description	Не более 10000 ссылок за запрос.
+//	@Description	Ошибка в одной строке не прерывает пакет: результат возвращается для каждой строки отдельно.
+//	@Description	dedupe в пакетном режиме не поддерживается.
+//	@Tags			shortlink
+//	@Accept			json
+//	@Accept			text/csv
+//	@Produce		json
+//	@Param			request	body		[]models.CreateShortLinkRequest	true	"Ссылки для создания"
+//	@Success		200		{object}	models.BatchCreateResponse
+//	@Failure		400		{string}	string	"bad request"
+//	@Failure		401		{string}	string	"api key required"
+//	@Failure		413		{string}	string	"batch too large"
+//	@Failure		500		{string}	string	"internal error"
+//	@Security		BearerAuth
+//	@Router			/shorten/batch [post]
+func (c *ShortLinkController) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ownerID := auth.OwnerID(ctx)
+	if ownerID == nil && !c.allowAnonymousCreate {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="shortener"`)
+		http.Error(w, "api key required", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchBodySize)
+
+	var reqs []models.CreateShortLinkRequest
+	var rowErrs []error
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err == nil && mediaType == "text/csv" {
+		reqs, rowErrs, err = models.ParseCreateShortLinkCSV(r.Body)
+	} else {
+		err = json.NewDecoder(r.Body).Decode(&reqs)
+	}
+
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "batch too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(reqs) == 0 {
+		http.Error(w, "batch is empty", http.StatusBadRequest)
+		return
+	}
+
+	if len(reqs) > maxBatchItems {
+		http.Error(w, fmt.Sprintf("batch too large: at most %d items", maxBatchItems), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	res := models.BatchCreateResponse{Results: make([]models.BatchCreateItemResult, len(reqs))}
+	params := make([]shortlink.CreateParams, 0, len(reqs))
+	indexes := make([]int, 0, len(reqs))
+
+	for i, req := range reqs {
+		res.Results[i].Index = i
+
+		if i < len(rowErrs) && rowErrs[i] != nil {
+			res.Results[i].Error = rowErrs[i].Error()
+			continue
+		}
+
+		if req.Dedupe {
+			res.Results[i].Error = "dedupe is not supported in batch mode"
+			continue
+		}
+
+		if err := c.validator.StructCtx(ctx, req); err != nil {
+			res.Results[i].Error = err.Error()
+			continue
+		}
+
+		p := req.ToCreateParams()
+		p.OwnerID = ownerID
+
+		p.Domain, err = c.createDomain(r, req)
+		if err != nil {
+			res.Results[i].Error = err.Error()
+			continue
+		}
+		params = append(params, p)
+		indexes = append(indexes, i)
+	}
+
+	if len(params) > 0 {
+		created, err := c.shortLinkService.CreateBatch(ctx, params)
+		if err != nil {
+			logger.Error("failed to create short links batch", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		links := make([]shortlink.ShortLink, 0, len(created))
+		for j, i := range indexes {
+			if created[j].Err != nil {
+				res.Results[i].Error = created[j].Err.Error()
+				continue
+			}
+
+			link := models.ShortLinkToResponse(*created[j].Link)
+			res.Results[i].Link = &link
+			links = append(links, *created[j].Link)
+		}
+
+		c.requestMetadata(ctx, links...)
+	}
+
+	for _, item := range res.Results {
+		if item.Error != "" {
+			res.Failed++
+		} else {
+			res.Created++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(res); err != nil {
 		logger.Error("failed to write response", "err", err)
 	}
@@ -87,38 +349,864 @@ func (c *ShortLinkController) Create(w http.ResponseWriter, r *http.Request) {
 //
 //	@Summary		Перенаправить по короткой ссылке
 //	@Description	Ищет короткую ссылку, регистрирует визит и перенаправляет на исходный URL.
+//	@Description	Для ссылки с паролем без действующей cookie разблокировки отдаёт HTML-форму ввода пароля.
+//	@Description	Для истёкшей ссылки или ссылки с исчерпанным лимитом переходов перенаправляет на fallbackURL,
+//	@Description	если он задан, иначе отвечает 410.
+//	@Description	Если исходный URL запрещён политикой URL (например, домен добавлен в блок-лист), отвечает 403.
+//	@Description	Код ответа задаётся ссылкой (redirectCode: 301, 302, 307 или 308). Временные редиректы отдаются
+//	@Description	с Cache-Control: no-store, постоянные кэшируются браузером, и повторные переходы не учитываются.
+//	@Description	HEAD возвращает те же заголовки без регистрации визита и без списания перехода.
+//	@Description	При queryPassthrough=target|request параметры запроса добавляются к целевому URL (при совпадении
+//	@Description	имён побеждает целевой URL или запрос соответственно). При pathPassthrough=true ссылка также
+//	@Description	обслуживает /s/{short_url}/{path...}, дописывая path к пути целевого URL; без него такой путь даёт 404.
+//	@Description	Правила ссылки (rules) проверяются по порядку: первое правило, под которое подходят устройство и ОС
+//	@Description	из User-Agent, язык из Accept-Language, страна и время запроса, задаёт целевой URL вместо исходного.
+//	@Description	Сработавшее правило сохраняется в визите.
+//	@Description	Посетители, которым не подошло ни одно правило, распределяются по вариантам (variants) пропорционально
+//	@Description	весам; при stickyVariants=true выбранный вариант запоминается в cookie. Вариант сохраняется в визите.
+//	@Description	Для ссылки с deepLink посетителям с iOS и Android, для которых задан адрес приложения, отдаётся
+//	@Description	HTML-страница, которая открывает приложение и через 2 секунды переходит на webURL (или обычный
+//	@Description	целевой URL). Визит регистрируется по отчёту страницы о результате (POST /handoff/{short_url}).
+//	@Description	Код с суффиксом + (/s/abc+) вместо перехода показывает страницу предпросмотра: целевой URL, его домен,
+//	@Description	дату создания и число переходов; визит не регистрируется. Для ссылок с alwaysPreview=true эта страница
+//	@Description	показывается перед каждым переходом, переход по ней ведёт на целевой URL.
 //	@Tags			shortlink
-//	@Param			short_url	path	string	true	"Короткий код"
+//	@Param			short_url	path		string	true	"Короткий код"
+//	@Success		200			{string}	string	"unlock form, preview or handoff page"
+//	@Success		301			"Moved Permanently"
 //	@Success		302			"Redirect"
+//	@Success		307			"Temporary Redirect"
+//	@Success		308			"Permanent Redirect"
+//	@Failure		403			{string}	string	"target URL is not allowed"
 //	@Failure		404			{string}	string	"short link not found"
+//	@Failure		410			{string}	string	"short link deleted, expired or click limit reached"
 //	@Failure		500			{string}	string	"internal error"
 //	@Router			/s/{short_url} [get]
+//	@Router			/s/{short_url} [head]
 func (c *ShortLinkController) Redirect(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	shortURL := chi.URLParam(r, "short_url")
+	shortURL, preview := strings.CutSuffix(chi.URLParam(r, "short_url"), "+")
 
-	shortLink, err := c.shortLinkService.Get(ctx, shortURL)
+	domain, err := c.domainService.Resolve(ctx, r.Host)
 	if err != nil {
-		if errors.Is(err, shortlink.ErrShortLinkNotFound) {
-			http.Error(w, err.Error(), http.StatusNotFound)
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	shortLink, err := c.shortLinkService.Get(ctx, domain, shortURL)
+	if err != nil {
+		// A preview reports an expired link rather than following its fallback URL.
+		if preview {
+			shortLink = nil
+		}
+
+		writeShortLinkError(w, r, c.withAllowedFallback(r, shortLink), err)
+		return
+	}
+
+	now := time.Now()
+	visitor := c.visitor(r, shortLink, now)
+	rawQuery, fromQR := shortlink.StripQRMarker(r.URL.RawQuery)
+	dest, err := shortLink.Route(visitor, passthroughPath(r), rawQuery)
+	if err != nil {
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	target, appURL := dest.URL, ""
+	if shortLink.DeepLink != nil {
+		if app, web := shortLink.DeepLink.Handoff(visitor.OS, dest.URL); app != "" {
+			target, appURL = web, app
+		}
+	}
+
+	if err := c.urlPolicy.Check(ctx, target); err != nil {
+		if errors.Is(err, shortlink.ErrTargetNotAllowed) {
+			http.Error(w, err.Error(), http.StatusForbidden)
 			return
 		}
 
-		logger.Error("failed to get short link", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeShortLinkError(w, r, shortLink, err)
+		return
 	}
 
-	visit := visit.Visit{
+	continueURL := r.URL.RequestURI()
+	if preview {
+		continueURL = "/s/" + shortURL + strings.TrimPrefix(continueURL, "/s/"+shortURL+"+")
+	}
+
+	// The unlock cookie is scoped to the link's own path, so the preview of a protected link asks for the
+	// password and then follows the link.
+	if shortLink.Protected() && (preview || !c.unlocked(r, shortLink)) {
+		public.RenderUnlockPage(w, http.StatusOK, continueURL, "")
+		return
+	}
+
+	if preview || shortLink.AlwaysPreview && !previewShown(r, shortLink) {
+		c.renderPreview(w, r, shortLink, target, continueURL)
+		return
+	}
+
+	if r.Method == http.MethodHead {
+		if shortLink.Exhausted() {
+			writeShortLinkError(w, r, c.withAllowedFallback(r, shortLink), shortlink.ErrShortLinkExhausted)
+			return
+		}
+
+		if appURL != "" {
+			c.renderHandoff(w, shortLink, dest, appURL, target, fromQR)
+			return
+		}
+
+		redirect(w, r, shortLink, dest.URL)
+		return
+	}
+
+	if err := c.shortLinkService.ConsumeClick(ctx, shortLink); err != nil {
+		writeShortLinkError(w, r, c.withAllowedFallback(r, shortLink), err)
+		return
+	}
+
+	if dest.Variant != nil && shortLink.StickyVariants {
+		rememberVariant(w, r, shortLink, dest.Variant.Name)
+	}
+
+	if shortLink.AlwaysPreview {
+		forgetPreview(w, r, shortLink)
+	}
+
+	// The visit of a handoff is registered once the page reports whether the app opened.
+	if appURL != "" {
+		c.renderHandoff(w, shortLink, dest, appURL, target, fromQR)
+		return
+	}
+
+	redirectVisit := visit.Visit{
+		ID:        uuid.New(),
+		LinkID:    shortLink.ID,
+		CreatedAt: now,
+		UserAgent: r.UserAgent(),
+		IPAddress: r.RemoteAddr,
+		Source:    visitSource(fromQR),
+	}
+	if dest.Rule != nil {
+		redirectVisit.Rule = dest.Rule.Name
+	}
+	if dest.Variant != nil {
+		redirectVisit.Variant = dest.Variant.Name
+	}
+
+	if err := c.visitService.Register(ctx, redirectVisit); err != nil {
+		logger.Error("failed to register visit", "err", err)
+	}
+
+	redirect(w, r, shortLink, dest.URL)
+}
+
+// Handoff godoc
+//
+//	@Summary		Записать исход перехода по диплинку
+//	@Description	Вызывается страницей передачи в приложение: регистрирует визит с путём app, если приложение
+//	@Description	открылось, или web, если посетитель ушёл на веб-адрес. rule и variant — правило и вариант,
+//	@Description	выбравшие веб-адрес; неизвестные значения игнорируются.
+//	@Description	token — одноразовый подписанный токен страницы, привязанный к ссылке, rule, variant и source;
+//	@Description	без действующего токена и, для ссылки с паролем, без cookie разблокировки отчёт отклоняется.
+//	@Tags			shortlink
+//	@Accept			x-www-form-urlencoded
+//	@Param			short_url	path		string	true	"Короткий код"
+//	@Param			path		formData	string	true	"Исход"	Enums(app,web)
+//	@Param			token		formData	string	true	"Токен страницы передачи"
+//	@Param			rule		formData	string	false	"Сработавшее правило"
+//	@Param			variant		formData	string	false	"Выбранный вариант"
+//	@Param			source		formData	string	false	"Источник перехода"	Enums(qr)
+//	@Success		204			"No Content"
+//	@Failure		400			{string}	string	"invalid handoff path"
+//	@Failure		403			{string}	string	"invalid handoff token or short link is locked"
+//	@Failure		404			{string}	string	"short link not found"
+//	@Failure		410			{string}	string	"short link deleted or expired"
+//	@Failure		500			{string}	string	"internal error"
+//	@Router			/handoff/{short_url} [post]
+func (c *ShortLinkController) Handoff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortURL := chi.URLParam(r, "short_url")
+
+	domain, err := c.domainService.Resolve(ctx, r.Host)
+	if err != nil {
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	shortLink, err := c.shortLinkService.Get(ctx, domain, shortURL)
+	if err != nil {
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	if shortLink.DeepLink == nil {
+		writeShortLinkError(w, r, nil, shortlink.ErrShortLinkNotFound)
+		return
+	}
+
+	// Unlock scopes its cookie to this path too, so a report needs the same unlock as the redirect.
+	if shortLink.Protected() && !c.unlocked(r, shortLink) {
+		http.Error(w, "short link is locked", http.StatusForbidden)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxHandoffFormSize)
+	path := r.PostFormValue("path")
+	if path != visit.HandoffApp && path != visit.HandoffWeb {
+		http.Error(w, "invalid handoff path", http.StatusBadRequest)
+		return
+	}
+
+	report := shortlink.HandoffReport{
+		Rule:    r.PostFormValue("rule"),
+		Variant: r.PostFormValue("variant"),
+		Source:  r.PostFormValue("source"),
+	}
+
+	// The page was rendered after the redirect took the click from the budget, and its token is single-use,
+	// so a redeemed token is the budget check of this visit.
+	if !c.handoffService.Redeem(ctx, shortLink, report, r.PostFormValue("token")) {
+		http.Error(w, "invalid handoff token", http.StatusForbidden)
+		return
+	}
+
+	handoffVisit := visit.Visit{
 		ID:        uuid.New(),
 		LinkID:    shortLink.ID,
 		CreatedAt: time.Now(),
 		UserAgent: r.UserAgent(),
 		IPAddress: r.RemoteAddr,
+		Handoff:   path,
+		Source:    visitSource(report.Source == visit.SourceQR),
 	}
 
-	if err := c.visitService.Register(ctx, visit); err != nil {
+	if slices.ContainsFunc(shortLink.Rules, func(r shortlink.Rule) bool { return r.Name == report.Rule }) {
+		handoffVisit.Rule = report.Rule
+	}
+
+	if slices.ContainsFunc(shortLink.Variants, func(v shortlink.Variant) bool { return v.Name == report.Variant }) {
+		handoffVisit.Variant = report.Variant
+	}
+
+	if err := c.visitService.Register(ctx, handoffVisit); err != nil {
 		logger.Error("failed to register visit", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Unlock godoc
+//
+//	@Summary		Разблокировать ссылку с паролем
+//	@Description	Проверяет пароль, выставляет подписанную cookie разблокировки и перенаправляет на короткую ссылку.
+//	@Description	Неудачные попытки ограничены по IP и коду.
+//	@Tags			shortlink
+//	@Accept			x-www-form-urlencoded
+//	@Produce		html
+//	@Param			short_url	path		string	true	"Короткий код"
+//	@Param			password	formData	string	true	"Пароль"
+//	@Success		303			"Redirect"
+//	@Failure		401			{string}	string	"invalid password"
+//	@Failure		404			{string}	string	"short link not found"
+//	@Failure		429			{string}	string	"too many unlock attempts"
+//	@Failure		500			{string}	string	"internal error"
+//	@Router			/s/{short_url} [post]
+func (c *ShortLinkController) Unlock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortURL := chi.URLParam(r, "short_url")
+
+	domain, err := c.domainService.Resolve(ctx, r.Host)
+	if err != nil {
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	shortLink, err := c.shortLinkService.Get(ctx, domain, shortURL)
+	if err != nil {
+		writeShortLinkError(w, r, shortLink, err)
+		return
+	}
+
+	if !shortLink.Protected() {
+		http.Redirect(w, r, r.URL.RequestURI(), http.StatusSeeOther)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUnlockFormSize)
+	token, err := c.unlockService.Unlock(ctx, shortLink, r.PostFormValue("password"), clientIP(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, shortlink.ErrInvalidPassword):
+			public.RenderUnlockPage(w, http.StatusUnauthorized, r.URL.RequestURI(), "Неверный пароль")
+		case errors.Is(err, shortlink.ErrTooManyUnlockAttempts):
+			public.RenderUnlockPage(
+				w,
+				http.StatusTooManyRequests,
+				r.URL.RequestURI(),
+				"Слишком много попыток, попробуйте позже",
+			)
+		default:
+			logger.Error("failed to unlock short link", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	// The handoff page of a protected link reports to /handoff/, which checks the unlock as well.
+	for _, path := range []string{"/s/", "/handoff/"} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     unlockCookiePrefix + shortLink.ShortCode,
+			Value:    token.Value,
+			Path:     path + shortLink.ShortCode,
+			Expires:  token.ExpiresAt,
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	http.Redirect(w, r, r.URL.RequestURI(), http.StatusSeeOther)
+}
+
+// List godoc
+//
+//	@Summary		Список коротких ссылок
+//	@Description	Постраничный список неудалённых ссылок с курсорной пагинацией.
+//	@Description	По API-ключу возвращаются только ссылки его владельца; с токеном администратора — все ссылки,
+//	@Description	фильтр owner доступен только администратору.
+//	@Description	Для следующей страницы передайте nextCursor из ответа в параметре cursor
+//	@Description	с теми же фильтрами и сортировкой.
+//	@Tags			shortlink
+//	@Produce		json
+//	@Param			createdFrom	query		string	false	"Создана не раньше (RFC3339)"
+//	@Param			createdTo	query		string	false	"Создана раньше (RFC3339)"
+//	@Param			domain		query		string	false	"Домен целевого URL"
+//	@Param			q			query		string	false	"Подстрока целевого URL"
+//	@Param			tag			query		string	false	"Тег ссылки"
+//	@Param			owner		query		string	false	"ID владельца"
+//	@Param			sort		query		string	false	"Сортировка"	Enums(createdAt, totalClicks)
+//	@Param			order		query		string	false	"Направление"	Enums(asc, desc)
+//	@Param			limit		query		int		false	"Размер страницы, по умолчанию 50, не более 1000"
+//	@Param			cursor		query		string	false	"Курсор следующей страницы"
+//	@Success		200			{object}	models.ListShortLinksResponse
+//	@Failure		400			{string}	string	"bad request"
+//	@Failure		401			{string}	string	"api key required"
+//	@Failure		403			{string}	string	"owner filter is admin only"
+//	@Failure		500			{string}	string	"internal error"
+//	@Security		BearerAuth
+//	@Router			/links [get]
+func (c *ShortLinkController) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := models.ParseListShortLinksRequest(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !auth.IsAdmin(ctx) {
+		ownerID := auth.OwnerID(ctx)
+		if ownerID == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="shortener"`)
+			http.Error(w, "api key required", http.StatusUnauthorized)
+			return
+		}
+
+		if req.OwnerID != nil && *req.OwnerID != *ownerID {
+			http.Error(w, "owner filter is admin only", http.StatusForbidden)
+			return
+		}
+		req.OwnerID = ownerID
+	}
+
+	page, err := c.shortLinkService.List(ctx, req.ToListParams())
+	if err != nil {
+		logger.Error("failed to list short links", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res := models.ListPageToResponse(*page, req.Sort)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Error("failed to write response", "err", err)
+	}
+}
+
+// Update godoc
+//
+//	@Summary		Изменить короткую ссылку
+//	@Description	Меняет целевой URL и/или код редиректа ссылки и сбрасывает её запись в кэше.
+//	@Description	Пустые title или description удаляют название или описание ссылки.
+//	@Tags			shortlink
+//	@Accept			json
+//	@Produce		json
+//	@Param			short_url	path		string							true	"Короткий код"
+//	@Param			domain		query		string							false	"Брендированный домен ссылки"
+//	@Param			request		body		models.UpdateShortLinkRequest	true	"Изменяемые поля"
+//	@Success		200			{object}	models.ShortLinkResponse
+//	@Failure		400			{string}	string	"bad request"
+//	@Failure		401			{string}	string	"api key required"
+//	@Failure		403			{string}	string	"short link belongs to another owner"
+//	@Failure		404			{string}	string	"short link not found"
+//	@Failure		409			{string}	string	"target already shortened by this owner"
+//	@Failure		410			{string}	string	"short link deleted"
+//	@Failure		422			{string}	string	"target URL is not allowed"
+//	@Failure		500			{string}	string	"internal error"
+//	@Security		BearerAuth
+//	@Router			/links/{short_url} [patch]
+func (c *ShortLinkController) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortURL := chi.URLParam(r, "short_url")
+
+	var req models.UpdateShortLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := c.validator.StructCtx(ctx, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.Empty() {
+		http.Error(w, "nothing to update", http.StatusBadRequest)
+		return
+	}
+
+	shortLink, err := c.shortLinkService.Update(ctx, auth.OwnerID(ctx), linkDomain(r), shortURL, req.ToUpdateParams())
+	if err != nil {
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	if req.OriginalURL != nil && shortLink.Metadata == nil {
+		c.requestMetadata(ctx, *shortLink)
+	}
+
+	res := models.ShortLinkToResponse(*shortLink)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Error("failed to write response", "err", err)
+	}
+}
+
+// Delete godoc
+//
+//	@Summary		Удалить короткую ссылку
+//	@Description	Помечает ссылку удалённой и сбрасывает её запись в кэше. Код удалённой ссылки не выдаётся повторно.
+//	@Tags			shortlink
+//	@Param			short_url	path	string	true	"Короткий код"
+//	@Param			domain		query	string	false	"Брендированный домен ссылки"
+//	@Success		204			"No Content"
+//	@Failure		401			{string}	string	"api key required"
+//	@Failure		403			{string}	string	"short link belongs to another owner"
+//	@Failure		404			{string}	string	"short link not found"
+//	@Failure		410			{string}	string	"short link deleted"
+//	@Failure		500			{string}	string	"internal error"
+//	@Security		BearerAuth
+//	@Router			/links/{short_url} [delete]
+func (c *ShortLinkController) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortURL := chi.URLParam(r, "short_url")
+
+	if err := c.shortLinkService.Delete(ctx, auth.OwnerID(ctx), linkDomain(r), shortURL); err != nil {
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AttachTags godoc
+//
+//	@Summary		Добавить теги ссылке
+//	@Description	Добавляет ссылке теги владельца; уже добавленные теги пропускаются.
+//	@Description	Теги приводятся к нижнему регистру, не длиннее 64 символов и не содержат '/'.
+//	@Description	У ссылки может быть не больше 50 тегов.
+//	@Tags			shortlink
+//	@Accept			json
+//	@Produce		json
+//	@Param			short_url	path		string				true	"Короткий код"
+//	@Param			domain		query		string				false	"Брендированный домен ссылки"
+//	@Param			request		body		models.TagsRequest	true	"Добавляемые теги"
+//	@Success		200			{object}	models.ShortLinkResponse
+//	@Failure		400			{string}	string	"invalid tag"
+//	@Failure		401			{string}	string	"api key required"
+//	@Failure		403			{string}	string	"short link belongs to another owner"
+//	@Failure		404			{string}	string	"short link not found"
+//	@Failure		410			{string}	string	"short link deleted"
+//	@Failure		500			{string}	string	"internal error"
+//	@Security		BearerAuth
+//	@Router			/links/{short_url}/tags [post]
+func (c *ShortLinkController) AttachTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortURL := chi.URLParam(r, "short_url")
+
+	var req models.TagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := c.validator.StructCtx(ctx, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	shortLink, err := c.shortLinkService.AttachTags(ctx, auth.OwnerID(ctx), linkDomain(r), shortURL, req.Tags)
+	if err != nil {
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	res := models.ShortLinkToResponse(*shortLink)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Error("failed to write response", "err", err)
+	}
+}
+
+// DetachTag godoc
+//
+//	@Summary	Убрать тег у ссылки
+//	@Tags		shortlink
+//	@Param		short_url	path	string	true	"Короткий код"
+//	@Param		tag			path	string	true	"Тег"
+//	@Param		domain		query	string	false	"Брендированный домен ссылки"
+//	@Success	204			"No Content"
+//	@Failure	400			{string}	string	"invalid tag"
+//	@Failure	401			{string}	string	"api key required"
+//	@Failure	403			{string}	string	"short link belongs to another owner"
+//	@Failure	404			{string}	string	"tag is not attached to the short link"
+//	@Failure	410			{string}	string	"short link deleted"
+//	@Failure	500			{string}	string	"internal error"
+//	@Security	BearerAuth
+//	@Router		/links/{short_url}/tags/{tag} [delete]
+func (c *ShortLinkController) DetachTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shortURL := chi.URLParam(r, "short_url")
+	tag := chi.URLParam(r, "tag")
+
+	if err := c.shortLinkService.DetachTag(ctx, auth.OwnerID(ctx), linkDomain(r), shortURL, tag); err != nil {
+		writeShortLinkError(w, r, nil, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *ShortLinkController) unlocked(r *http.Request, shortLink *shortlink.ShortLink) bool {
+	cookie, err := r.Cookie(unlockCookiePrefix + shortLink.ShortCode)
+	if err != nil {
+		return false
+	}
+
+	return c.unlockService.Verify(shortLink, cookie.Value)
+}
+
+// withAllowedFallback drops a fallback URL that the URL policy rejects now, so blocking a domain
+// also stops redirects to it from expired links.
+func (c *ShortLinkController) withAllowedFallback(
+	r *http.Request,
+	shortLink *shortlink.ShortLink,
+) *shortlink.ShortLink {
+	if shortLink == nil || shortLink.FallbackURL == "" {
+		return shortLink
+	}
+
+	if err := c.urlPolicy.Check(r.Context(), shortLink.FallbackURL); err != nil {
+		link := *shortLink
+		link.FallbackURL = ""
+		return &link
+	}
+
+	return shortLink
+}
+
+// writeAliasConflict answers a taken custom alias with free alternatives the client may retry with.
+func (c *ShortLinkController) writeAliasConflict(
+	w http.ResponseWriter,
+	r *http.Request,
+	domain, alias string,
+	conflict error,
+) {
+	res := models.ShortLinkConflictResponse{
+		Error:       conflict.Error(),
+		ShortURL:    alias,
+		Suggestions: make([]string, 0),
+	}
+
+	suggestions, err := c.shortLinkService.SuggestAliases(r.Context(), domain, alias)
+	if err != nil {
+		logger.Error("failed to suggest aliases", "err", err)
+	}
+
+	for _, code := range suggestions {
+		if c.validator.Var(code, validation.AliasTag) == nil {
+			res.Suggestions = append(res.Suggestions, code)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Error("failed to write response", "err", err)
+	}
+}
+
+// redirect sends the client to the link target with the link's status code. Temporary redirects must not be
+// cached, so every visit reaches the shortener; permanent ones may be cached until the link expires.
+func redirect(w http.ResponseWriter, r *http.Request, shortLink *shortlink.ShortLink, target string) {
+	if shortLink.PermanentRedirect() && !shortLink.Personalized() {
+		maxAge := permanentRedirectMaxAge
+		if shortLink.ExpiresAt != nil {
+			maxAge = min(maxAge, time.Until(*shortLink.ExpiresAt))
+		}
+
+		scope := "public"
+		if shortLink.Protected() {
+			scope = "private"
+		}
+
+		w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, int(max(maxAge, 0).Seconds())))
+	} else {
+		w.Header().Set("Cache-Control", "private, no-store")
+	}
+
+	http.Redirect(w, r, target, shortLink.RedirectStatus())
+}
+
+func writeShortLinkError(w http.ResponseWriter, r *http.Request, shortLink *shortlink.ShortLink, err error) {
+	switch {
+	case errors.Is(err, shortlink.ErrShortLinkNotFound), errors.Is(err, shortlink.ErrTagNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, shortlink.ErrShortLinkDeleted):
+		http.Error(w, err.Error(), http.StatusGone)
+	case errors.Is(err, shortlink.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, shortlink.ErrTargetAlreadyShortened):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, shortlink.ErrUnknownDomain), errors.Is(err, shortlink.ErrInvalidPassthrough),
+		errors.Is(err, shortlink.ErrInvalidRule), errors.Is(err, shortlink.ErrInvalidVariant),
+		errors.Is(err, shortlink.ErrInvalidDeepLink), errors.Is(err, shortlink.ErrInvalidTag):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, shortlink.ErrTargetNotAllowed):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, shortlink.ErrShortLinkExpired), errors.Is(err, shortlink.ErrShortLinkExhausted):
+		if shortLink != nil && shortLink.FallbackURL != "" {
+			http.Redirect(w, r, shortLink.FallbackURL, http.StatusFound)
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusGone)
+	default:
+		logger.Error("failed to get short link", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// createDomain returns the registered domain requested for a new link, or "" for the default domain.
+func (c *ShortLinkController) createDomain(r *http.Request, req models.CreateShortLinkRequest) (string, error) {
+	if req.Domain == nil {
+		return "", nil
+	}
+
+	domain, err := c.domainService.Resolve(r.Context(), *req.Domain)
+	if err != nil {
+		return "", err
+	}
+
+	if domain == "" {
+		return "", shortlink.ErrUnknownDomain
+	}
+
+	return domain, nil
+}
+
+// linkDomain reads the domain of a managed link from the query, since management requests use the API host.
+func linkDomain(r *http.Request) string {
+	return strings.ToLower(r.URL.Query().Get("domain"))
+}
+
+// visitor describes the request for routing it. Links without rules or a deep link skip the lookups, and only
+// links with variants read the variant cookie and roll for a variant.
+func (c *ShortLinkController) visitor(r *http.Request, link *shortlink.ShortLink, now time.Time) shortlink.Visitor {
+	visitor := shortlink.Visitor{Time: now}
+	if len(link.Rules) > 0 || link.DeepLink != nil {
+		visitor = shortlink.NewVisitor(
+			r.UserAgent(),
+			r.Header.Get("Accept-Language"),
+			c.countryResolver.Country(clientIP(r), r.Header),
+			now,
+		)
+	}
+
+	if len(link.Variants) > 0 {
+		visitor.Roll = rand.Float64()
+		if cookie, err := r.Cookie(variantCookiePrefix + link.ShortCode); err == nil {
+			if variant, err := url.QueryUnescape(cookie.Value); err == nil {
+				visitor.Variant = variant
+			}
+		}
+	}
+
+	return visitor
+}
+
+func (c *ShortLinkController) renderHandoff(
+	w http.ResponseWriter,
+	shortLink *shortlink.ShortLink,
+	dest shortlink.Destination,
+	appURL, webURL string,
+	fromQR bool,
+) {
+	report := shortlink.HandoffReport{Source: visitSource(fromQR)}
+	if dest.Rule != nil {
+		report.Rule = dest.Rule.Name
+	}
+	if dest.Variant != nil {
+		report.Variant = dest.Variant.Name
+	}
+
+	token, err := c.handoffService.Issue(shortLink, report)
+	if err != nil {
+		logger.Error("failed to issue handoff token", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	public.RenderHandoffPage(w, public.HandoffPage{
+		AppURL:    appURL,
+		WebURL:    webURL,
+		ReportURL: "/handoff/" + url.PathEscape(shortLink.ShortCode),
+		Token:     token,
+		Rule:      report.Rule,
+		Variant:   report.Variant,
+		Source:    report.Source,
+	})
+}
+
+// rememberVariant keeps the visitor on the same variant of a sticky split on later visits.
+func rememberVariant(w http.ResponseWriter, r *http.Request, shortLink *shortlink.ShortLink, variant string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     variantCookiePrefix + shortLink.ShortCode,
+		Value:    url.QueryEscape(variant),
+		Path:     "/s/" + shortLink.ShortCode,
+		MaxAge:   int(variantCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// renderPreview shows where the link leads without counting a visit. For links that always show the preview
+// it also remembers that it was shown, so following it redirects once.
+func (c *ShortLinkController) renderPreview(
+	w http.ResponseWriter,
+	r *http.Request,
+	shortLink *shortlink.ShortLink,
+	target, continueURL string,
+) {
+	totalClicks, err := c.visitService.TotalVisits(r.Context(), shortLink.ID)
+	if err != nil {
+		logger.Error("failed to read total visits", "err", err)
+		totalClicks = shortLink.TotalClicks
+	}
+
+	page := public.PreviewPage{
+		TargetURL:   target,
+		CreatedAt:   shortLink.CreatedAt,
+		TotalClicks: totalClicks,
+		ContinueURL: continueURL,
+	}
+	if u, err := url.Parse(target); err == nil {
+		page.TargetHost = u.Hostname()
+	}
+
+	// Metadata describes the link's main target; rules and variants may lead to other sites.
+	if metadata := shortLink.Metadata; metadata != nil && page.TargetHost == hostname(shortLink.OriginalURL) {
+		page.PageTitle = metadata.Title
+		page.PageDescription = metadata.Description
+		page.ImageURL = metadata.ImageURL
+		page.FaviconURL = metadata.FaviconURL
+	}
+
+	// Nobody sees the page of a HEAD request, so it must not let the next GET skip the preview.
+	if shortLink.AlwaysPreview && r.Method != http.MethodHead {
+		http.SetCookie(w, previewCookie(r, shortLink, int(previewCookieMaxAge.Seconds())))
+	}
+
+	public.RenderPreviewPage(w, page)
+}
+
+// requestMetadata queues fetching the targets' metadata; a failure leaves the links without it.
+func (c *ShortLinkController) requestMetadata(ctx context.Context, links ...shortlink.ShortLink) {
+	if err := c.metadataService.Request(ctx, links...); err != nil {
+		logger.Error("failed to request link metadata", "err", err)
+	}
+}
+
+func hostname(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Hostname()
+}
+
+func previewShown(r *http.Request, shortLink *shortlink.ShortLink) bool {
+	_, err := r.Cookie(previewCookiePrefix + shortLink.ShortCode)
+	return err == nil
+}
+
+func forgetPreview(w http.ResponseWriter, r *http.Request, shortLink *shortlink.ShortLink) {
+	http.SetCookie(w, previewCookie(r, shortLink, -1))
+}
+
+func previewCookie(r *http.Request, shortLink *shortlink.ShortLink, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     previewCookiePrefix + shortLink.ShortCode,
+		Value:    "1",
+		Path:     "/s/" + shortLink.ShortCode,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// passthroughPath returns the request path after /s/{short_url}/ as sent, still escaped: chi's "*" parameter
+// is already decoded, and Route unescapes the suffix itself.
+func passthroughPath(r *http.Request) string {
+	_, extraPath, _ := strings.Cut(strings.TrimPrefix(r.URL.EscapedPath(), "/s/"), "/")
+	return extraPath
+}
+
+func visitSource(fromQR bool) string {
+	if fromQR {
+		return visit.SourceQR
+	}
+
+	return ""
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
 	}
 
-	http.Redirect(w, r, shortLink.OriginalURL, http.StatusFound)
+	return host
 }
@@ -0,0 +1,129 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"shortener/src/internal/application/services"
+	"shortener/src/internal/application/services/mocks"
+	shortlink "shortener/src/internal/domain/short_link"
+	"shortener/src/internal/infrastructure/geoip"
+	"shortener/src/internal/web_api/controllers"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+type allowAllURLs struct{}
+
+func (allowAllURLs) Check(context.Context, string) error {
+	return nil
+}
+
+type fixedQRCodes struct{}
+
+func (fixedQRCodes) Render(context.Context, string, shortlink.QRCodeOptions) ([]byte, error) {
+	return []byte("qr"), nil
+}
+
+func newRouter(t *testing.T, link *shortlink.ShortLink) http.Handler {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mocks.NewMockShortLinkRepository(ctrl)
+	mockRepo.EXPECT().Get(gomock.Any(), "", link.ShortCode).Return(link, nil).AnyTimes()
+
+	mockCache := mocks.NewMockCache(ctrl)
+	mockCache.EXPECT().Get(gomock.Any(), gomock.Any()).Return("", errors.New("not found")).AnyTimes()
+	mockCache.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	mockProducer := mocks.NewMockMessageProducer(ctrl)
+	mockProducer.EXPECT().Produce(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	shortLinkService := services.NewShortLinkService(mockRepo, nil, nil, 1, 32, allowAllURLs{}, mockCache, false)
+	domainService := services.NewDomainService(mocks.NewMockDomainRepository(ctrl), mockCache, []string{"example.com"})
+	visitService := services.NewVisitService(mocks.NewMockVisitRepository(ctrl), mockProducer)
+
+	controller := controllers.NewShortLinkController(
+		shortLinkService,
+		domainService,
+		nil,
+		nil,
+		allowAllURLs{},
+		visitService,
+		nil,
+		geoip.NewHeaderResolver(nil),
+		nil,
+		false,
+	)
+
+	qrCodeController := controllers.NewQRCodeController(
+		shortLinkService, domainService, fixedQRCodes{}, time.Hour, controller.Redirect,
+	)
+
+	router := chi.NewRouter()
+	controller.UseHandlers(router)
+	qrCodeController.UseHandlers(router)
+
+	return router
+}
+
+func TestShortLinkController_Redirect_PathPassthroughKeepsEscapes(t *testing.T) {
+	link := &shortlink.ShortLink{
+		ID:              uuid.New(),
+		ShortCode:       "abc",
+		OriginalURL:     "https://target.example/base",
+		PathPassthrough: true,
+	}
+	router := newRouter(t, link)
+
+	cases := map[string]string{
+		"/s/abc/100%25":   "https://target.example/base/100%25",
+		"/s/abc/%2541":    "https://target.example/base/%2541",
+		"/s/abc/a%2Fb/c":  "https://target.example/base/a%2Fb/c",
+		"/s/abc/docs/%20": "https://target.example/base/docs/%20",
+	}
+	for path, want := range cases {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+
+		if rec.Code != http.StatusFound {
+			t.Fatalf("%s: expected 302, got %d: %s", path, rec.Code, rec.Body.String())
+		}
+		if got := rec.Header().Get("Location"); got != want {
+			t.Fatalf("%s: expected Location %q, got %q", path, want, got)
+		}
+	}
+}
+
+func TestQRCodeController_QRCode_PathPassthrough(t *testing.T) {
+	link := &shortlink.ShortLink{
+		ID:          uuid.New(),
+		ShortCode:   "abc",
+		OriginalURL: "https://target.example/base",
+	}
+
+	rec := httptest.NewRecorder()
+	newRouter(t, link).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com/s/abc/qr", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "qr" {
+		t.Fatalf("expected the QR code, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	link.PathPassthrough = true
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		rec = httptest.NewRecorder()
+		newRouter(t, link).ServeHTTP(rec, httptest.NewRequest(method, "http://example.com/s/abc/qr?size=x", nil))
+
+		if rec.Code != http.StatusFound {
+			t.Fatalf("%s: expected 302, got %d: %s", method, rec.Code, rec.Body.String())
+		}
+		if got := rec.Header().Get("Location"); got != "https://target.example/base/qr" {
+			t.Fatalf("%s: expected the passthrough redirect, got Location %q", method, got)
+		}
+	}
+}
@@ -15,26 +15,27 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/analytics/{short_url}": {
+        "/analytics": {
             "get": {
-                "description": "Возвращает статистику переходов, агрегированную по дням, месяцам или User-Agent.",
+                "description": "Возвращает статистику переходов по всем неудалённым ссылкам с тегом,\nагрегированную по дням, месяцам, User-Agent или источнику.\nПо API-ключу учитываются только ссылки его владельца; с токеном администратора — ссылки всех владельцев.",
                 "tags": [
                     "analytics"
                 ],
-                "summary": "Получить аналитику по короткой ссылке",
+                "summary": "Получить аналитику по тегу",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Короткий код",
-                        "name": "short_url",
-                        "in": "path",
+                        "description": "Тег",
+                        "name": "tag",
+                        "in": "query",
                         "required": true
                     },
                     {
                         "enum": [
                             "day",
                             "month",
-                            "userAgent"
+                            "userAgent",
+                            "source"
                         ],
                         "type": "string",
                         "description": "Тип группировки",
@@ -54,22 +55,33 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "401": {
+                        "description": "api key required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal error",
                         "schema": {
                             "type": "string"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/s/{short_url}": {
+        "/analytics/{short_url}": {
             "get": {
-                "description": "Ищет короткую ссылку, регистрирует визит и перенаправляет на исходный URL.",
+                "description": "Возвращает статистику переходов, агрегированную по дням, месяцам или User-Agent.\nГруппировка budget возвращает лимит переходов и его остаток.\nГруппировка variant сравнивает число переходов и их долю по вариантам A/B-теста.\nГруппировка source разделяет сканирования QR-кода (qr) и обычные переходы (direct).\nАналитика ссылки с владельцем доступна только по API-ключу владельца.",
                 "tags": [
-                    "shortlink"
+                    "analytics"
                 ],
-                "summary": "Перенаправить по короткой ссылке",
+                "summary": "Получить аналитику по короткой ссылке",
                 "parameters": [
                     {
                         "type": "string",
@@ -77,11 +89,51 @@ const docTemplate = `{
                         "name": "short_url",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Брендированный домен ссылки",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "month",
+                            "userAgent",
+                            "budget",
+                            "variant",
+                            "source"
+                        ],
+                        "type": "string",
+                        "description": "Тип группировки",
+                        "name": "group",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "302": {
-                        "description": "Redirect"
+                    "200": {
+                        "description": "Результат зависит от типа группировки",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "unknown group",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid api key",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "short link belongs to another owner",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
                     "404": {
                         "description": "short link not found",
@@ -95,12 +147,17 @@ const docTemplate = `{
                             "type": "string"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/shorten": {
+        "/api-keys": {
             "post": {
-                "description": "Создаёт новую короткую ссылку. Если shortCode не задан, он генерируется.",
+                "description": "Создаёт API-ключ. Ключ возвращается только в этом ответе, в базе хранится его хэш.\nЕсли ownerID не задан, создаётся новый владелец. Требует ADMIN_TOKEN.",
                 "consumes": [
                     "application/json"
                 ],
@@ -108,17 +165,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "shortlink"
+                    "api-keys"
                 ],
-                "summary": "Создать короткую ссылку",
+                "summary": "Выпустить API-ключ",
                 "parameters": [
                     {
-                        "description": "Данные для создания короткой ссылки",
+                        "description": "Параметры ключа",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateShortLinkRequest"
+                            "$ref": "#/definitions/models.CreateAPIKeyRequest"
                         }
                     }
                 ],
@@ -126,7 +183,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.CreateShortLinkResponse"
+                            "$ref": "#/definitions/models.CreateAPIKeyResponse"
                         }
                     },
                     "400": {
@@ -135,8 +192,14 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "409": {
-                        "description": "short link already exists",
+                    "401": {
+                        "description": "admin token required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "admin api is disabled",
                         "schema": {
                             "type": "string"
                         }
@@ -147,42 +210,1705 @@ const docTemplate = `{
                             "type": "string"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
-        }
-    },
-    "definitions": {
-        "models.CreateShortLinkRequest": {
-            "type": "object",
-            "required": [
-                "originalURL"
-            ],
-            "properties": {
-                "originalURL": {
-                    "type": "string"
+        },
+        "/api-keys/{id}": {
+            "delete": {
+                "description": "Отзывает API-ключ. Требует ADMIN_TOKEN.",
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Отозвать API-ключ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ключа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "admin token required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "admin api is disabled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "api key not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 },
-                "shortURL": {
-                    "type": "string",
-                    "maxLength": 6
-                }
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "models.CreateShortLinkResponse": {
-            "type": "object",
-            "properties": {
-                "createdAt": {
-                    "type": "string"
+        "/domains": {
+            "get": {
+                "description": "Требует ADMIN_TOKEN.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "domains"
+                ],
+                "summary": "Список брендированных доменов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DomainResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "admin token required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "admin api is disabled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 },
-                "id": {
-                    "type": "string"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Добавляет домен, с которого обслуживаются короткие ссылки. DNS домена должен указывать на сервис.\nТребует ADMIN_TOKEN.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "domains"
+                ],
+                "summary": "Зарегистрировать брендированный домен",
+                "parameters": [
+                    {
+                        "description": "Домен",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateDomainRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "admin token required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "admin api is disabled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "domain already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 },
-                "originalURL": {
-                    "type": "string"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/domains/{host}": {
+            "delete": {
+                "description": "Удаляет домен, если на нём нет коротких ссылок. Требует ADMIN_TOKEN.",
+                "tags": [
+                    "domains"
+                ],
+                "summary": "Удалить брендированный домен",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Домен",
+                        "name": "host",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "admin token required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "admin api is disabled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "domain not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "domain has short links",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 },
-                "shortCode": {
-                    "type": "string"
-                }
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
+        },
+        "/handoff/{short_url}": {
+            "post": {
+                "description": "Вызывается страницей передачи в приложение: регистрирует визит с путём app, если приложение\nоткрылось, или web, если посетитель ушёл на веб-адрес. rule и variant — правило и вариант,\nвыбравшие веб-адрес; неизвестные значения игнорируются.\ntoken — одноразовый подписанный токен страницы, привязанный к ссылке, rule, variant и source;\nбез действующего токена и, для ссылки с паролем, без cookie разблокировки отчёт отклоняется.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Записать исход перехода по диплинку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Короткий код",
+                        "name": "short_url",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "app",
+                            "web"
+                        ],
+                        "type": "string",
+                        "description": "Исход",
+                        "name": "path",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен страницы передачи",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сработавшее правило",
+                        "name": "rule",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Выбранный вариант",
+                        "name": "variant",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "qr"
+                        ],
+                        "type": "string",
+                        "description": "Источник перехода",
+                        "name": "source",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "invalid handoff path",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "invalid handoff token or short link is locked",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "short link not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "short link deleted or expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/links": {
+            "get": {
+                "description": "Постраничный список неудалённых ссылок с курсорной пагинацией.\nПо API-ключу возвращаются только ссылки его владельца; с токеном администратора — все ссылки,\nфильтр owner доступен только администратору.\nДля следующей страницы передайте nextCursor из ответа в параметре cursor\nс теми же фильтрами и сортировкой.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Список коротких ссылок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Создана не раньше (RFC3339)",
+                        "name": "createdFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создана раньше (RFC3339)",
+                        "name": "createdTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Домен целевого URL",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока целевого URL",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тег ссылки",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID владельца",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "createdAt",
+                            "totalClicks"
+                        ],
+                        "type": "string",
+                        "description": "Сортировка",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Направление",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, не более 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ListShortLinksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "api key required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "owner filter is admin only",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/links/{short_url}": {
+            "delete": {
+                "description": "Помечает ссылку удалённой и сбрасывает её запись в кэше. Код удалённой ссылки не выдаётся повторно.",
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Удалить короткую ссылку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Короткий код",
+                        "name": "short_url",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Брендированный домен ссылки",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "api key required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "short link belongs to another owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "short link not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "short link deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Меняет целевой URL и/или код редиректа ссылки и сбрасывает её запись в кэше.\nПустые title или description удаляют название или описание ссылки.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Изменить короткую ссылку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Короткий код",
+                        "name": "short_url",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Брендированный домен ссылки",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateShortLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShortLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "api key required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "short link belongs to another owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "short link not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "target already shortened by this owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "short link deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "target URL is not allowed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/links/{short_url}/tags": {
+            "post": {
+                "description": "Добавляет ссылке теги владельца; уже добавленные теги пропускаются.\nТеги приводятся к нижнему регистру, не длиннее 64 символов и не содержат '/'.\nУ ссылки может быть не больше 50 тегов.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Добавить теги ссылке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Короткий код",
+                        "name": "short_url",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Брендированный домен ссылки",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Добавляемые теги",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShortLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid tag",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "api key required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "short link belongs to another owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "short link not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "short link deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/links/{short_url}/tags/{tag}": {
+            "delete": {
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Убрать тег у ссылки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Короткий код",
+                        "name": "short_url",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тег",
+                        "name": "tag",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Брендированный домен ссылки",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "invalid tag",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "api key required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "short link belongs to another owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "tag is not attached to the short link",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "short link deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/s/{short_url}": {
+            "get": {
+                "description": "Ищет короткую ссылку, регистрирует визит и перенаправляет на исходный URL.\nДля ссылки с паролем без действующей cookie разблокировки отдаёт HTML-форму ввода пароля.\nДля истёкшей ссылки или ссылки с исчерпанным лимитом переходов перенаправляет на fallbackURL,\nесли он задан, иначе отвечает 410.\nЕсли исходный URL запрещён политикой URL (например, домен добавлен в блок-лист), отвечает 403.\nКод ответа задаётся ссылкой (redirectCode: 301, 302, 307 или 308). Временные редиректы отдаются\nс Cache-Control: no-store, постоянные кэшируются браузером, и повторные переходы не учитываются.\nHEAD возвращает те же заголовки без регистрации визита и без списания перехода.\nПри queryPassthrough=target|request параметры запроса добавляются к целевому URL (при совпадении\nимён побеждает целевой URL или запрос соответственно). При pathPassthrough=true ссылка также\nобслуживает /s/{short_url}/{path...}, дописывая path к пути целевого URL; без него такой путь даёт 404.\nПравила ссылки (rules) проверяются по порядку: первое правило, под которое подходят устройство и ОС\nиз User-Agent, язык из Accept-Language, страна и время запроса, задаёт целевой URL вместо исходного.\nСработавшее правило сохраняется в визите.\nПосетители, которым не подошло ни одно правило, распределяются по вариантам (variants) пропорционально\nвесам; при stickyVariants=true выбранный вариант запоминается в cookie. Вариант сохраняется в визите.\nДля ссылки с deepLink посетителям с iOS и Android, для которых задан адрес приложения, отдаётся\nHTML-страница, которая открывает приложение и через 2 секунды переходит на webURL (или обычный\nцелевой URL). Визит регистрируется по отчёту страницы о результате (POST /handoff/{short_url}).\nКод с суффиксом + (/s/abc+) вместо перехода показывает страницу предпросмотра: целевой URL, его домен,\nдату создания и число переходов; визит не регистрируется. Для ссылок с alwaysPreview=true эта страница\nпоказывается перед каждым переходом, переход по ней ведёт на целевой URL.",
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Перенаправить по короткой ссылке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Короткий код",
+                        "name": "short_url",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "unlock form, preview or handoff page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "301": {
+                        "description": "Moved Permanently"
+                    },
+                    "302": {
+                        "description": "Redirect"
+                    },
+                    "307": {
+                        "description": "Temporary Redirect"
+                    },
+                    "308": {
+                        "description": "Permanent Redirect"
+                    },
+                    "403": {
+                        "description": "target URL is not allowed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "short link not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "short link deleted, expired or click limit reached",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Проверяет пароль, выставляет подписанную cookie разблокировки и перенаправляет на короткую ссылку.\nНеудачные попытки ограничены по IP и коду.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Разблокировать ссылку с паролем",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Короткий код",
+                        "name": "short_url",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Пароль",
+                        "name": "password",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "303": {
+                        "description": "Redirect"
+                    },
+                    "401": {
+                        "description": "invalid password",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "short link not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "too many unlock attempts",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Ищет короткую ссылку, регистрирует визит и перенаправляет на исходный URL.\nДля ссылки с паролем без действующей cookie разблокировки отдаёт HTML-форму ввода пароля.\nДля истёкшей ссылки или ссылки с исчерпанным лимитом переходов перенаправляет на fallbackURL,\nесли он задан, иначе отвечает 410.\nЕсли исходный URL запрещён политикой URL (например, домен добавлен в блок-лист), отвечает 403.\nКод ответа задаётся ссылкой (redirectCode: 301, 302, 307 или 308). Временные редиректы отдаются\nс Cache-Control: no-store, постоянные кэшируются браузером, и повторные переходы не учитываются.\nHEAD возвращает те же заголовки без регистрации визита и без списания перехода.\nПри queryPassthrough=target|request параметры запроса добавляются к целевому URL (при совпадении\nимён побеждает целевой URL или запрос соответственно). При pathPassthrough=true ссылка также\nобслуживает /s/{short_url}/{path...}, дописывая path к пути целевого URL; без него такой путь даёт 404.\nПравила ссылки (rules) проверяются по порядку: первое правило, под которое подходят устройство и ОС\nиз User-Agent, язык из Accept-Language, страна и время запроса, задаёт целевой URL вместо исходного.\nСработавшее правило сохраняется в визите.\nПосетители, которым не подошло ни одно правило, распределяются по вариантам (variants) пропорционально\nвесам; при stickyVariants=true выбранный вариант запоминается в cookie. Вариант сохраняется в визите.\nДля ссылки с deepLink посетителям с iOS и Android, для которых задан адрес приложения, отдаётся\nHTML-страница, которая открывает приложение и через 2 секунды переходит на webURL (или обычный\nцелевой URL). Визит регистрируется по отчёту страницы о результате (POST /handoff/{short_url}).\nКод с суффиксом + (/s/abc+) вместо перехода показывает страницу предпросмотра: целевой URL, его домен,\nдату создания и число переходов; визит не регистрируется. Для ссылок с alwaysPreview=true эта страница\nпоказывается перед каждым переходом, переход по ней ведёт на целевой URL.",
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Перенаправить по короткой ссылке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Короткий код",
+                        "name": "short_url",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "unlock form, preview or handoff page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "301": {
+                        "description": "Moved Permanently"
+                    },
+                    "302": {
+                        "description": "Redirect"
+                    },
+                    "307": {
+                        "description": "Temporary Redirect"
+                    },
+                    "308": {
+                        "description": "Permanent Redirect"
+                    },
+                    "403": {
+                        "description": "target URL is not allowed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "short link not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "short link deleted, expired or click limit reached",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/s/{short_url}/qr": {
+            "get": {
+                "description": "Возвращает QR-код полного короткого URL в PNG или SVG. В QR-код попадает метка ?src=qr: при переходе\nона удаляется, а визит сохраняется с источником qr (группировка source в статистике).\nДля ссылки с pathPassthrough=true путь /qr передаётся в целевой URL, как любой другой путь.",
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Получить QR-код короткой ссылки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Короткий код",
+                        "name": "short_url",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "png",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "description": "Формат, по умолчанию png",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ширина в пикселях, от 64 до 2048, по умолчанию 256",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "L",
+                            "M",
+                            "Q",
+                            "H"
+                        ],
+                        "type": "string",
+                        "description": "Уровень коррекции ошибок, по умолчанию M",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Поле в модулях, от 0 до 16, по умолчанию 4",
+                        "name": "margin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Цвет модулей RRGGBB, по умолчанию 000000",
+                        "name": "fg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Цвет фона RRGGBB, по умолчанию ffffff",
+                        "name": "bg",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "QR-код",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid options",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "short link not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "short link deleted, expired or click limit reached",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/shorten": {
+            "post": {
+                "description": "Создаёт новую короткую ссылку. Если shortCode не задан, он генерируется.\nСсылка, созданная по API-ключу, принадлежит его владельцу. Без ключа создание доступно,\nтолько если включено ALLOW_ANONYMOUS_CREATE.\nС dedupe=true возвращает уже существующую ссылку владельца на тот же URL с кодом 200.\ndedupe требует API-ключ. Истёкшая или исчерпанная ссылка заменяется новой, а действующая\nс другими expiresAt, maxClicks, fallbackURL или паролем даёт 409.\ndomain задаёт зарегистрированный брендированный домен; код уникален в пределах домена.\nЕсли свой shortURL занят, ответ 409 содержит свободные варианты в suggestions.\noriginalURL и fallbackURL проверяются политикой URL: схема, блок- и allow-листы доменов,\nдругие сокращатели и ссылки на сам сервис; нарушение даёт 422.\nСвой код из списка зарезервированных слов или с нецензурным словом отклоняется с кодом 422.\nЗаголовок, описание, картинка и иконка целевой страницы загружаются в фоне и появляются в поле metadata.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Создать короткую ссылку",
+                "parameters": [
+                    {
+                        "description": "Данные для создания короткой ссылки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateShortLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "existing link (dedupe)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShortLinkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShortLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "api key required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "short link already exists or dedupe options differ",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShortLinkConflictResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "short code or target URL is not allowed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/shorten/batch": {
+            "post": {
+                "description": "Принимает JSON-массив CreateShortLinkRequest или CSV (Content-Type: text/csv) с заголовком\noriginalURL,domain,shortURL,expiresAt,fallbackURL,maxClicks,password,redirectCode,queryPassthrough,\npathPassthrough,title,description.\nНе более 10000 ссылок за запрос.\nОшибка в одной строке не прерывает пакет: результат возвращается для каждой строки отдельно.\ndedupe в пакетном режиме не поддерживается.",
+                "consumes": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shortlink"
+                ],
+                "summary": "Создать короткие ссылки пакетом",
+                "parameters": [
+                    {
+                        "description": "Ссылки для создания",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CreateShortLinkRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "api key required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "batch too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "models.BatchCreateItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "link": {
+                    "$ref": "#/definitions/models.ShortLinkResponse"
+                }
+            }
+        },
+        "models.BatchCreateResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchCreateItemResult"
+                    }
+                }
+            }
+        },
+        "models.CreateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "ownerID": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ownerID": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateDomainRequest": {
+            "type": "object",
+            "required": [
+                "host"
+            ],
+            "properties": {
+                "host": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateShortLinkRequest": {
+            "type": "object",
+            "required": [
+                "originalURL"
+            ],
+            "properties": {
+                "alwaysPreview": {
+                    "type": "boolean"
+                },
+                "dedupe": {
+                    "type": "boolean"
+                },
+                "deepLink": {
+                    "$ref": "#/definitions/models.DeepLink"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "fallbackURL": {
+                    "type": "string"
+                },
+                "maxClicks": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "originalURL": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 4
+                },
+                "pathPassthrough": {
+                    "type": "boolean"
+                },
+                "queryPassthrough": {
+                    "type": "string",
+                    "enum": [
+                        "off",
+                        "target",
+                        "request"
+                    ]
+                },
+                "redirectCode": {
+                    "type": "integer",
+                    "enum": [
+                        301,
+                        302,
+                        307,
+                        308
+                    ]
+                },
+                "rules": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/models.RedirectRule"
+                    }
+                },
+                "shortURL": {
+                    "type": "string"
+                },
+                "stickyVariants": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "variants": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "$ref": "#/definitions/models.SplitVariant"
+                    }
+                }
+            }
+        },
+        "models.DeepLink": {
+            "type": "object",
+            "properties": {
+                "androidURL": {
+                    "type": "string"
+                },
+                "iosURL": {
+                    "type": "string"
+                },
+                "webURL": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DomainResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ListShortLinksResponse": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ShortLinkResponse"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Metadata": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "faviconURL": {
+                    "type": "string"
+                },
+                "fetchedAt": {
+                    "type": "string"
+                },
+                "imageURL": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RedirectRule": {
+            "type": "object",
+            "required": [
+                "name",
+                "targetURL"
+            ],
+            "properties": {
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "os": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "schedule": {
+                    "$ref": "#/definitions/models.RuleSchedule"
+                },
+                "targetURL": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RuleSchedule": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.ShortLinkConflictResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "shortURL": {
+                    "type": "string"
+                },
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ShortLinkResponse": {
+            "type": "object",
+            "properties": {
+                "alwaysPreview": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deepLink": {
+                    "$ref": "#/definitions/models.DeepLink"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "fallbackURL": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "maxClicks": {
+                    "type": "integer"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/models.Metadata"
+                },
+                "originalURL": {
+                    "type": "string"
+                },
+                "ownerID": {
+                    "type": "string"
+                },
+                "passwordProtected": {
+                    "type": "boolean"
+                },
+                "pathPassthrough": {
+                    "type": "boolean"
+                },
+                "queryPassthrough": {
+                    "type": "string"
+                },
+                "rawURL": {
+                    "type": "string"
+                },
+                "redirectCode": {
+                    "type": "integer"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RedirectRule"
+                    }
+                },
+                "shortCode": {
+                    "type": "string"
+                },
+                "stickyVariants": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "totalClicks": {
+                    "type": "integer"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SplitVariant"
+                    }
+                },
+                "warning": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SplitVariant": {
+            "type": "object",
+            "required": [
+                "name",
+                "targetURL"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "targetURL": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer",
+                    "maximum": 1000000,
+                    "minimum": 0
+                }
+            }
+        },
+        "models.TagsRequest": {
+            "type": "object",
+            "required": [
+                "tags"
+            ],
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.UpdateShortLinkRequest": {
+            "type": "object",
+            "properties": {
+                "alwaysPreview": {
+                    "type": "boolean"
+                },
+                "deepLink": {
+                    "$ref": "#/definitions/models.DeepLink"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "originalURL": {
+                    "type": "string"
+                },
+                "pathPassthrough": {
+                    "type": "boolean"
+                },
+                "queryPassthrough": {
+                    "type": "string",
+                    "enum": [
+                        "off",
+                        "target",
+                        "request"
+                    ]
+                },
+                "redirectCode": {
+                    "type": "integer",
+                    "enum": [
+                        301,
+                        302,
+                        307,
+                        308
+                    ]
+                },
+                "rules": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/models.RedirectRule"
+                    }
+                },
+                "stickyVariants": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "description": "An empty title or description removes it.",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "variants": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "$ref": "#/definitions/models.SplitVariant"
+                    }
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "API-ключ в формате \"Bearer \u003ckey\u003e\".",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,31 @@
+package models
+
+import (
+	apikey "shortener/src/internal/domain/api_key"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateAPIKeyRequest struct {
+	Name    string     `json:"name" validate:"max=100"`
+	OwnerID *uuid.UUID `json:"ownerID,omitempty"`
+}
+
+type CreateAPIKeyResponse struct {
+	ID        uuid.UUID `json:"id"`
+	OwnerID   uuid.UUID `json:"ownerID"`
+	Name      string    `json:"name"`
+	Key       string    `json:"key"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func APIKeyToCreateResponse(key apikey.APIKey, token string) CreateAPIKeyResponse {
+	return CreateAPIKeyResponse{
+		ID:        key.ID,
+		OwnerID:   key.OwnerID,
+		Name:      key.Name,
+		Key:       token,
+		CreatedAt: key.CreatedAt,
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import shortlink "shortener/src/internal/domain/short_link"
+
+type DeepLink struct {
+	IOSURL     string `json:"iosURL,omitempty" validate:"omitempty,uri"`
+	AndroidURL string `json:"androidURL,omitempty" validate:"omitempty,uri"`
+	WebURL     string `json:"webURL,omitempty" validate:"omitempty,url"`
+}
+
+func DeepLinkToDomain(deepLink *DeepLink) *shortlink.DeepLink {
+	if deepLink == nil {
+		return nil
+	}
+
+	return &shortlink.DeepLink{
+		IOSURL:     deepLink.IOSURL,
+		AndroidURL: deepLink.AndroidURL,
+		WebURL:     deepLink.WebURL,
+	}
+}
+
+func DeepLinkToResponse(deepLink *shortlink.DeepLink) *DeepLink {
+	if deepLink == nil {
+		return nil
+	}
+
+	return &DeepLink{
+		IOSURL:     deepLink.IOSURL,
+		AndroidURL: deepLink.AndroidURL,
+		WebURL:     deepLink.WebURL,
+	}
+}
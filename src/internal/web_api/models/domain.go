@@ -0,0 +1,22 @@
+package models
+
+import (
+	brandeddomain "shortener/src/internal/domain/branded_domain"
+	"time"
+)
+
+type CreateDomainRequest struct {
+	Host string `json:"host" validate:"required,hostname"`
+}
+
+type DomainResponse struct {
+	Host      string    `json:"host"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func DomainToResponse(domain brandeddomain.Domain) DomainResponse {
+	return DomainResponse{
+		Host:      domain.Host,
+		CreatedAt: domain.CreatedAt,
+	}
+}
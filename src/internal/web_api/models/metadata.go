@@ -0,0 +1,28 @@
+package models
+
+import (
+	shortlink "shortener/src/internal/domain/short_link"
+	"time"
+)
+
+type Metadata struct {
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	ImageURL    string    `json:"imageURL,omitempty"`
+	FaviconURL  string    `json:"faviconURL,omitempty"`
+	FetchedAt   time.Time `json:"fetchedAt"`
+}
+
+func MetadataToResponse(metadata *shortlink.Metadata) *Metadata {
+	if metadata == nil {
+		return nil
+	}
+
+	return &Metadata{
+		Title:       metadata.Title,
+		Description: metadata.Description,
+		ImageURL:    metadata.ImageURL,
+		FaviconURL:  metadata.FaviconURL,
+		FetchedAt:   metadata.FetchedAt,
+	}
+}
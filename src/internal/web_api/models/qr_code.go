@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/hex"
+	"fmt"
+	"image/color"
+	"net/url"
+	shortlink "shortener/src/internal/domain/short_link"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultQRCodeSize   = 256
+	minQRCodeSize       = 64
+	maxQRCodeSize       = 2048
+	defaultQRCodeMargin = 4
+	maxQRCodeMargin     = 16
+)
+
+func ParseQRCodeOptions(query url.Values) (shortlink.QRCodeOptions, error) {
+	options := shortlink.QRCodeOptions{
+		Format:     shortlink.QRFormatPNG,
+		Size:       defaultQRCodeSize,
+		Level:      shortlink.QRLevelMedium,
+		Margin:     defaultQRCodeMargin,
+		Foreground: color.RGBA{A: 0xff},
+		Background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}
+
+	switch format := strings.ToLower(query.Get("format")); format {
+	case "":
+	case shortlink.QRFormatPNG, shortlink.QRFormatSVG:
+		options.Format = format
+	default:
+		return options, fmt.Errorf("invalid format %q: expected png or svg", format)
+	}
+
+	if size := query.Get("size"); size != "" {
+		value, err := strconv.Atoi(size)
+		if err != nil || value < minQRCodeSize || value > maxQRCodeSize {
+			return options, fmt.Errorf("invalid size: expected an integer from %d to %d", minQRCodeSize, maxQRCodeSize)
+		}
+		options.Size = value
+	}
+
+	switch level := strings.ToUpper(query.Get("level")); level {
+	case "":
+	case shortlink.QRLevelLow, shortlink.QRLevelMedium, shortlink.QRLevelQuartile, shortlink.QRLevelHigh:
+		options.Level = level
+	default:
+		return options, fmt.Errorf("invalid level %q: expected L, M, Q or H", level)
+	}
+
+	if margin := query.Get("margin"); margin != "" {
+		value, err := strconv.Atoi(margin)
+		if err != nil || value < 0 || value > maxQRCodeMargin {
+			return options, fmt.Errorf("invalid margin: expected an integer from 0 to %d", maxQRCodeMargin)
+		}
+		options.Margin = value
+	}
+
+	var err error
+	if options.Foreground, err = parseColorParam(query, "fg", options.Foreground); err != nil {
+		return options, err
+	}
+
+	if options.Background, err = parseColorParam(query, "bg", options.Background); err != nil {
+		return options, err
+	}
+
+	return options, nil
+}
+
+// parseColorParam reads an opaque RRGGBB colour; the leading '#' is optional since it has to be escaped in URLs.
+func parseColorParam(query url.Values, name string, fallback color.RGBA) (color.RGBA, error) {
+	value := strings.TrimPrefix(query.Get(name), "#")
+	if value == "" {
+		return fallback, nil
+	}
+
+	rgb, err := hex.DecodeString(value)
+	if err != nil || len(rgb) != 3 {
+		return fallback, fmt.Errorf("invalid %s: expected a colour as RRGGBB", name)
+	}
+
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xff}, nil
+}
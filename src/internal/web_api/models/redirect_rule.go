@@ -0,0 +1,89 @@
+package models
+
+import (
+	shortlink "shortener/src/internal/domain/short_link"
+	"time"
+)
+
+type RedirectRule struct {
+	Name      string        `json:"name" validate:"required,max=64"`
+	TargetURL string        `json:"targetURL" validate:"required,url"`
+	Devices   []string      `json:"devices,omitempty" validate:"omitempty,dive,oneof=mobile tablet desktop bot"`
+	OS        []string      `json:"os,omitempty" validate:"omitempty,dive,oneof=ios android windows macos linux"`
+	Languages []string      `json:"languages,omitempty" validate:"omitempty,dive,bcp47_language_tag"`
+	Countries []string      `json:"countries,omitempty" validate:"omitempty,dive,iso3166_1_alpha2"`
+	Schedule  *RuleSchedule `json:"schedule,omitempty"`
+}
+
+type RuleSchedule struct {
+	Start    *time.Time `json:"start,omitempty"`
+	End      *time.Time `json:"end,omitempty"`
+	Weekdays []int      `json:"weekdays,omitempty" validate:"omitempty,dive,min=0,max=6"`
+	From     string     `json:"from,omitempty" validate:"omitempty,datetime=15:04"`
+	To       string     `json:"to,omitempty" validate:"omitempty,datetime=15:04"`
+	Timezone string     `json:"timezone,omitempty" validate:"omitempty,timezone"`
+}
+
+func RedirectRulesToDomain(rules []RedirectRule) []shortlink.Rule {
+	if rules == nil {
+		return nil
+	}
+
+	result := make([]shortlink.Rule, len(rules))
+	for i, rule := range rules {
+		result[i] = shortlink.Rule{
+			Name:      rule.Name,
+			TargetURL: rule.TargetURL,
+			Devices:   rule.Devices,
+			OS:        rule.OS,
+			Languages: rule.Languages,
+			Countries: rule.Countries,
+		}
+
+		if s := rule.Schedule; s != nil {
+			schedule := &shortlink.Schedule{
+				Start:    s.Start,
+				End:      s.End,
+				From:     s.From,
+				To:       s.To,
+				Timezone: s.Timezone,
+			}
+			for _, day := range s.Weekdays {
+				schedule.Weekdays = append(schedule.Weekdays, time.Weekday(day))
+			}
+			result[i].Schedule = schedule
+		}
+	}
+
+	return result
+}
+
+func RedirectRulesToResponse(rules []shortlink.Rule) []RedirectRule {
+	result := make([]RedirectRule, len(rules))
+	for i, rule := range rules {
+		result[i] = RedirectRule{
+			Name:      rule.Name,
+			TargetURL: rule.TargetURL,
+			Devices:   rule.Devices,
+			OS:        rule.OS,
+			Languages: rule.Languages,
+			Countries: rule.Countries,
+		}
+
+		if s := rule.Schedule; s != nil {
+			schedule := &RuleSchedule{
+				Start:    s.Start,
+				End:      s.End,
+				From:     s.From,
+				To:       s.To,
+				Timezone: s.Timezone,
+			}
+			for _, day := range s.Weekdays {
+				schedule.Weekdays = append(schedule.Weekdays, int(day))
+			}
+			result[i].Schedule = schedule
+		}
+	}
+
+	return result
+}
@@ -2,14 +2,31 @@ package models
 
 import (
 	shortlink "shortener/src/internal/domain/short_link"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type CreateShortLinkRequest struct {
-	ShortURL    *string `json:"shortURL,omitempty" validate:"omitempty,max=6"`
-	OriginalURL string  `json:"originalURL" validate:"required,url"`
+	Domain           *string        `json:"domain,omitempty" validate:"omitempty,hostname"`
+	ShortURL         *string        `json:"shortURL,omitempty" validate:"omitempty,alias"`
+	OriginalURL      string         `json:"originalURL" validate:"required,url"`
+	ExpiresAt        *time.Time     `json:"expiresAt,omitempty" validate:"omitempty,gt"`
+	FallbackURL      *string        `json:"fallbackURL,omitempty" validate:"omitempty,url"`
+	MaxClicks        *int64         `json:"maxClicks,omitempty" validate:"omitempty,min=1"`
+	Password         *string        `json:"password,omitempty" validate:"omitempty,min=4,max=72"`
+	Dedupe           bool           `json:"dedupe,omitempty"`
+	RedirectCode     *int           `json:"redirectCode,omitempty" validate:"omitempty,oneof=301 302 307 308"`
+	QueryPassthrough *string        `json:"queryPassthrough,omitempty" validate:"omitempty,oneof=off target request"`
+	PathPassthrough  bool           `json:"pathPassthrough,omitempty"`
+	Rules            []RedirectRule `json:"rules,omitempty" validate:"omitempty,max=20,dive"`
+	Variants         []SplitVariant `json:"variants,omitempty" validate:"omitempty,max=10,dive"`
+	StickyVariants   bool           `json:"stickyVariants,omitempty"`
+	DeepLink         *DeepLink      `json:"deepLink,omitempty"`
+	AlwaysPreview    bool           `json:"alwaysPreview,omitempty"`
+	Title            string         `json:"title,omitempty" validate:"max=200"`
+	Description      string         `json:"description,omitempty" validate:"max=2000"`
 }
 
 func (r CreateShortLinkRequest) ShortURLString() string {
@@ -20,18 +37,177 @@ func (r CreateShortLinkRequest) ShortURLString() string {
 	return *r.ShortURL
 }
 
-type CreateShortLinkResponse struct {
-	ID          uuid.UUID `json:"id"`
-	ShortCode   string    `json:"shortCode"`
-	OriginalURL string    `json:"originalURL"`
-	CreatedAt   time.Time `json:"createdAt"`
+func (r CreateShortLinkRequest) ToCreateParams() shortlink.CreateParams {
+	params := shortlink.CreateParams{
+		ShortCode:   r.ShortURLString(),
+		OriginalURL: r.OriginalURL,
+		ExpiresAt:   r.ExpiresAt,
+		MaxClicks:   r.MaxClicks,
+	}
+
+	if r.FallbackURL != nil {
+		params.FallbackURL = *r.FallbackURL
+	}
+
+	if r.Password != nil {
+		params.Password = *r.Password
+	}
+
+	if r.RedirectCode != nil {
+		params.RedirectCode = *r.RedirectCode
+	}
+
+	if r.QueryPassthrough != nil {
+		params.QueryPassthrough = shortlink.QueryPassthrough(*r.QueryPassthrough)
+	}
+	params.PathPassthrough = r.PathPassthrough
+	params.Rules = RedirectRulesToDomain(r.Rules)
+	params.Variants = SplitVariantsToDomain(r.Variants)
+	params.StickyVariants = r.StickyVariants
+	params.DeepLink = DeepLinkToDomain(r.DeepLink)
+	params.AlwaysPreview = r.AlwaysPreview
+	params.Title = strings.TrimSpace(r.Title)
+	params.Description = strings.TrimSpace(r.Description)
+
+	return params
+}
+
+type TagsRequest struct {
+	Tags []string `json:"tags" validate:"required,min=1,max=50"`
+}
+
+type ShortLinkConflictResponse struct {
+	Error       string   `json:"error"`
+	ShortURL    string   `json:"shortURL"`
+	Suggestions []string `json:"suggestions"`
+}
+
+type UpdateShortLinkRequest struct {
+	OriginalURL      *string         `json:"originalURL,omitempty" validate:"omitempty,url"`
+	RedirectCode     *int            `json:"redirectCode,omitempty" validate:"omitempty,oneof=301 302 307 308"`
+	QueryPassthrough *string         `json:"queryPassthrough,omitempty" validate:"omitempty,oneof=off target request"`
+	PathPassthrough  *bool           `json:"pathPassthrough,omitempty"`
+	Rules            *[]RedirectRule `json:"rules,omitempty" validate:"omitempty,max=20,dive"`
+	Variants         *[]SplitVariant `json:"variants,omitempty" validate:"omitempty,max=10,dive"`
+	StickyVariants   *bool           `json:"stickyVariants,omitempty"`
+	DeepLink         *DeepLink       `json:"deepLink,omitempty"`
+	AlwaysPreview    *bool           `json:"alwaysPreview,omitempty"`
+	// An empty title or description removes it.
+	Title       *string `json:"title,omitempty" validate:"omitempty,max=200"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=2000"`
+}
+
+// Empty reports whether the request changes nothing.
+func (r UpdateShortLinkRequest) Empty() bool {
+	return r.OriginalURL == nil && r.RedirectCode == nil && r.QueryPassthrough == nil && r.PathPassthrough == nil &&
+		r.Rules == nil && r.Variants == nil && r.StickyVariants == nil && r.DeepLink == nil && r.AlwaysPreview == nil &&
+		r.Title == nil && r.Description == nil
+}
+
+func (r UpdateShortLinkRequest) ToUpdateParams() shortlink.UpdateParams {
+	params := shortlink.UpdateParams{
+		OriginalURL:     r.OriginalURL,
+		RedirectCode:    r.RedirectCode,
+		PathPassthrough: r.PathPassthrough,
+		StickyVariants:  r.StickyVariants,
+		DeepLink:        DeepLinkToDomain(r.DeepLink),
+		AlwaysPreview:   r.AlwaysPreview,
+	}
+
+	if r.QueryPassthrough != nil {
+		mode := shortlink.QueryPassthrough(*r.QueryPassthrough)
+		params.QueryPassthrough = &mode
+	}
+
+	if r.Title != nil {
+		title := strings.TrimSpace(*r.Title)
+		params.Title = &title
+	}
+
+	if r.Description != nil {
+		description := strings.TrimSpace(*r.Description)
+		params.Description = &description
+	}
+
+	if r.Rules != nil {
+		rules := RedirectRulesToDomain(*r.Rules)
+		params.Rules = &rules
+	}
+
+	if r.Variants != nil {
+		variants := SplitVariantsToDomain(*r.Variants)
+		params.Variants = &variants
+	}
+
+	return params
 }
 
-func ShortLinkToCreateResponse(shortLink shortlink.ShortLink) CreateShortLinkResponse {
-	return CreateShortLinkResponse{
-		ID:          shortLink.ID,
-		ShortCode:   shortLink.ShortCode,
-		OriginalURL: shortLink.OriginalURL,
-		CreatedAt:   shortLink.CreatedAt,
+type ShortLinkResponse struct {
+	ID               uuid.UUID      `json:"id"`
+	Domain           string         `json:"domain,omitempty"`
+	ShortCode        string         `json:"shortCode"`
+	OriginalURL      string         `json:"originalURL"`
+	RawURL           string         `json:"rawURL,omitempty"`
+	CreatedAt        time.Time      `json:"createdAt"`
+	ExpiresAt        *time.Time     `json:"expiresAt,omitempty"`
+	FallbackURL      string         `json:"fallbackURL,omitempty"`
+	MaxClicks        *int64         `json:"maxClicks,omitempty"`
+	Protected        bool           `json:"passwordProtected,omitempty"`
+	OwnerID          *uuid.UUID     `json:"ownerID,omitempty"`
+	TotalClicks      int64          `json:"totalClicks"`
+	RedirectCode     int            `json:"redirectCode"`
+	QueryPassthrough string         `json:"queryPassthrough"`
+	PathPassthrough  bool           `json:"pathPassthrough"`
+	Rules            []RedirectRule `json:"rules"`
+	Variants         []SplitVariant `json:"variants"`
+	StickyVariants   bool           `json:"stickyVariants"`
+	DeepLink         *DeepLink      `json:"deepLink,omitempty"`
+	AlwaysPreview    bool           `json:"alwaysPreview"`
+	Title            string         `json:"title,omitempty"`
+	Description      string         `json:"description,omitempty"`
+	Tags             []string       `json:"tags"`
+	Metadata         *Metadata      `json:"metadata,omitempty"`
+	Warning          string         `json:"warning,omitempty"`
+}
+
+const permanentRedirectWarning = "permanent redirects are cached by browsers: repeat visits skip the shortener, " +
+	"so they are not counted and later changes to the link may not reach returning visitors"
+
+func ShortLinkToResponse(shortLink shortlink.ShortLink) ShortLinkResponse {
+	res := ShortLinkResponse{
+		ID:               shortLink.ID,
+		Domain:           shortLink.Domain,
+		ShortCode:        shortLink.ShortCode,
+		OriginalURL:      shortLink.OriginalURL,
+		RawURL:           shortLink.RawURL,
+		CreatedAt:        shortLink.CreatedAt,
+		ExpiresAt:        shortLink.ExpiresAt,
+		FallbackURL:      shortLink.FallbackURL,
+		MaxClicks:        shortLink.MaxClicks,
+		Protected:        shortLink.Protected(),
+		OwnerID:          shortLink.OwnerID,
+		TotalClicks:      shortLink.TotalClicks,
+		RedirectCode:     shortLink.RedirectStatus(),
+		QueryPassthrough: string(shortLink.QueryPassthrough),
+		PathPassthrough:  shortLink.PathPassthrough,
+		Rules:            RedirectRulesToResponse(shortLink.Rules),
+		Variants:         SplitVariantsToResponse(shortLink.Variants),
+		StickyVariants:   shortLink.StickyVariants,
+		DeepLink:         DeepLinkToResponse(shortLink.DeepLink),
+		AlwaysPreview:    shortLink.AlwaysPreview,
+		Title:            shortLink.Title,
+		Description:      shortLink.Description,
+		Tags:             shortLink.Tags,
+		Metadata:         MetadataToResponse(shortLink.Metadata),
 	}
+
+	if res.Tags == nil {
+		res.Tags = []string{}
+	}
+
+	if shortLink.PermanentRedirect() {
+		res.Warning = permanentRedirectWarning
+	}
+
+	return res
 }
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type BatchCreateItemResult struct {
+	Index int                `json:"index"`
+	Link  *ShortLinkResponse `json:"link,omitempty"`
+	Error string             `json:"error,omitempty"`
+}
+
+type BatchCreateResponse struct {
+	Created int                     `json:"created"`
+	Failed  int                     `json:"failed"`
+	Results []BatchCreateItemResult `json:"results"`
+}
+
+// ParseCreateShortLinkCSV reads a CSV with a header row whose column names match
+// the JSON fields of CreateShortLinkRequest. Only originalURL is required.
+// A row that cannot be read does not fail the upload: its error is returned at
+// the row's index in rowErrs, next to an empty request.
+func ParseCreateShortLinkCSV(r io.Reader) (requests []CreateShortLinkRequest, rowErrs []error, err error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("csv header is missing")
+		}
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	if _, ok := columns["originalURL"]; !ok {
+		return nil, nil, errors.New("csv column originalURL is missing")
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return nil, nil, err
+		}
+
+		var req CreateShortLinkRequest
+		switch {
+		case err != nil:
+			err = fmt.Errorf("csv: %w", err)
+		case len(record) > len(header):
+			err = fmt.Errorf("csv line %d: %d fields, header has %d", line, len(record), len(header))
+		default:
+			req, err = csvRecordToRequest(columns, record)
+			if err != nil {
+				err = fmt.Errorf("csv line %d: %w", line, err)
+			}
+		}
+
+		requests = append(requests, req)
+		rowErrs = append(rowErrs, err)
+	}
+
+	return requests, rowErrs, nil
+}
+
+func csvRecordToRequest(columns map[string]int, record []string) (CreateShortLinkRequest, error) {
+	value := func(name string) (string, bool) {
+		i, ok := columns[name]
+		if !ok || i >= len(record) || strings.TrimSpace(record[i]) == "" {
+			return "", false
+		}
+		return strings.TrimSpace(record[i]), true
+	}
+
+	var req CreateShortLinkRequest
+	req.OriginalURL, _ = value("originalURL")
+
+	if v, ok := value("domain"); ok {
+		req.Domain = &v
+	}
+
+	if v, ok := value("shortURL"); ok {
+		req.ShortURL = &v
+	}
+
+	if v, ok := value("expiresAt"); ok {
+		expiresAt, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return req, fmt.Errorf("invalid expiresAt: %w", err)
+		}
+		req.ExpiresAt = &expiresAt
+	}
+
+	if v, ok := value("fallbackURL"); ok {
+		req.FallbackURL = &v
+	}
+
+	if v, ok := value("maxClicks"); ok {
+		maxClicks, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return req, fmt.Errorf("invalid maxClicks: %w", err)
+		}
+		req.MaxClicks = &maxClicks
+	}
+
+	if v, ok := value("password"); ok {
+		req.Password = &v
+	}
+
+	if v, ok := value("redirectCode"); ok {
+		redirectCode, err := strconv.Atoi(v)
+		if err != nil {
+			return req, fmt.Errorf("invalid redirectCode: %w", err)
+		}
+		req.RedirectCode = &redirectCode
+	}
+
+	if v, ok := value("queryPassthrough"); ok {
+		req.QueryPassthrough = &v
+	}
+
+	if v, ok := value("pathPassthrough"); ok {
+		pathPassthrough, err := strconv.ParseBool(v)
+		if err != nil {
+			return req, fmt.Errorf("invalid pathPassthrough: %w", err)
+		}
+		req.PathPassthrough = pathPassthrough
+	}
+
+	req.Title, _ = value("title")
+	req.Description, _ = value("description")
+
+	return req, nil
+}
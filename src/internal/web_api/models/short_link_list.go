@@ -0,0 +1,168 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	shortlink "shortener/src/internal/domain/short_link"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ListShortLinksRequest struct {
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	TargetDomain   string
+	TargetContains string
+	Tag            string
+	OwnerID        *uuid.UUID
+	Sort           shortlink.ListSort
+	Ascending      bool
+	Limit          int
+	Cursor         *shortlink.ListCursor
+}
+
+type ListShortLinksResponse struct {
+	Links      []ShortLinkResponse `json:"links"`
+	NextCursor string              `json:"nextCursor,omitempty"`
+}
+
+type listCursor struct {
+	Sort shortlink.ListSort `json:"sort"`
+	shortlink.ListCursor
+}
+
+func ParseListShortLinksRequest(query url.Values) (ListShortLinksRequest, error) {
+	var req ListShortLinksRequest
+	var err error
+
+	if req.CreatedFrom, err = parseTimeParam(query, "createdFrom"); err != nil {
+		return req, err
+	}
+
+	if req.CreatedTo, err = parseTimeParam(query, "createdTo"); err != nil {
+		return req, err
+	}
+
+	req.TargetDomain = query.Get("domain")
+	req.TargetContains = query.Get("q")
+
+	if tag := query.Get("tag"); tag != "" {
+		req.Tag, err = shortlink.NormalizeTag(tag)
+		if err != nil {
+			return req, err
+		}
+	}
+
+	if owner := query.Get("owner"); owner != "" {
+		ownerID, err := uuid.Parse(owner)
+		if err != nil {
+			return req, fmt.Errorf("invalid owner: %w", err)
+		}
+		req.OwnerID = &ownerID
+	}
+
+	switch sort := shortlink.ListSort(query.Get("sort")); sort {
+	case "", shortlink.ListSortCreatedAt:
+		req.Sort = shortlink.ListSortCreatedAt
+	case shortlink.ListSortTotalClicks:
+		req.Sort = sort
+	default:
+		return req, fmt.Errorf("invalid sort %q: expected createdAt or totalClicks", sort)
+	}
+
+	switch order := query.Get("order"); order {
+	case "", "desc":
+	case "asc":
+		req.Ascending = true
+	default:
+		return req, fmt.Errorf("invalid order %q: expected asc or desc", order)
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		req.Limit, err = strconv.Atoi(limit)
+		if err != nil || req.Limit < 1 {
+			return req, errors.New("invalid limit: expected a positive integer")
+		}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		req.Cursor, err = decodeListCursor(cursor, req.Sort)
+		if err != nil {
+			return req, err
+		}
+	}
+
+	return req, nil
+}
+
+func (r ListShortLinksRequest) ToListParams() shortlink.ListParams {
+	return shortlink.ListParams{
+		Filter: shortlink.ListFilter{
+			OwnerID:        r.OwnerID,
+			CreatedFrom:    r.CreatedFrom,
+			CreatedTo:      r.CreatedTo,
+			TargetDomain:   r.TargetDomain,
+			TargetContains: r.TargetContains,
+			Tag:            r.Tag,
+		},
+		Sort:      r.Sort,
+		Ascending: r.Ascending,
+		Cursor:    r.Cursor,
+		Limit:     r.Limit,
+	}
+}
+
+func ListPageToResponse(page shortlink.ListPage, sort shortlink.ListSort) ListShortLinksResponse {
+	res := ListShortLinksResponse{Links: make([]ShortLinkResponse, 0, len(page.Links))}
+	for _, link := range page.Links {
+		res.Links = append(res.Links, ShortLinkToResponse(link))
+	}
+
+	if page.NextCursor != nil {
+		res.NextCursor = encodeListCursor(*page.NextCursor, sort)
+	}
+
+	return res
+}
+
+func encodeListCursor(cursor shortlink.ListCursor, sort shortlink.ListSort) string {
+	data, _ := json.Marshal(listCursor{Sort: sort, ListCursor: cursor}) //nolint: errcheck // plain struct
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeListCursor(value string, sort shortlink.ListSort) (*shortlink.ListCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor listCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	if cursor.Sort != sort {
+		return nil, errors.New("cursor was issued for another sort")
+	}
+
+	return &cursor.ListCursor, nil
+}
+
+func parseTimeParam(query url.Values, name string) (*time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,39 @@
+package models
+
+import shortlink "shortener/src/internal/domain/short_link"
+
+type SplitVariant struct {
+	Name      string `json:"name" validate:"required,max=64"`
+	TargetURL string `json:"targetURL" validate:"required,url"`
+	Weight    int    `json:"weight" validate:"min=0,max=1000000"`
+}
+
+func SplitVariantsToDomain(variants []SplitVariant) []shortlink.Variant {
+	if variants == nil {
+		return nil
+	}
+
+	result := make([]shortlink.Variant, len(variants))
+	for i, variant := range variants {
+		result[i] = shortlink.Variant{
+			Name:      variant.Name,
+			TargetURL: variant.TargetURL,
+			Weight:    variant.Weight,
+		}
+	}
+
+	return result
+}
+
+func SplitVariantsToResponse(variants []shortlink.Variant) []SplitVariant {
+	result := make([]SplitVariant, len(variants))
+	for i, variant := range variants {
+		result[i] = SplitVariant{
+			Name:      variant.Name,
+			TargetURL: variant.TargetURL,
+			Weight:    variant.Weight,
+		}
+	}
+
+	return result
+}
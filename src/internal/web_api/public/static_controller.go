@@ -2,8 +2,10 @@ package public
 
 import (
 	"embed"
+	"html/template"
 	"net/http"
 	"shortener/src/pkg/logger"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -11,6 +13,13 @@ import (
 //go:embed *.html
 var htmlFS embed.FS
 
+var unlockTemplate = template.Must(template.ParseFS(htmlFS, "unlock.html"))
+var handoffTemplate = template.Must(template.ParseFS(htmlFS, "handoff.html"))
+var previewTemplate = template.Must(template.ParseFS(htmlFS, "preview.html"))
+
+// handoffTimeout is how long the handoff page waits for the app to open before falling back to the web URL.
+const handoffTimeout = 2 * time.Second
+
 func UseStaticFiles(r chi.Router) {
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		data, err := htmlFS.ReadFile("index.html")
@@ -40,3 +49,89 @@ func UseStaticFiles(r chi.Router) {
 		}
 	})
 }
+
+// RenderUnlockPage renders the password form; action is the request URI the form posts back to.
+func RenderUnlockPage(w http.ResponseWriter, status int, action, errMsg string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	err := unlockTemplate.Execute(w, struct {
+		Action string
+		Error  string
+	}{
+		Action: action,
+		Error:  errMsg,
+	})
+	if err != nil {
+		logger.Error("failed to write unlock.html", "err", err)
+	}
+}
+
+// HandoffPage is what the deep link handoff page needs: the URLs to try, where to report the outcome and
+// the token, rule, variant and visit source echoed back in the report.
+type HandoffPage struct {
+	AppURL    string
+	WebURL    string
+	ReportURL string
+	Token     string
+	Rule      string
+	Variant   string
+	Source    string
+}
+
+// RenderHandoffPage renders the page that tries to open the app and falls back to the web URL after
+// handoffTimeout. The app URL is passed as trusted: it may use any app scheme and is validated when saved.
+func RenderHandoffPage(w http.ResponseWriter, page HandoffPage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+
+	err := handoffTemplate.Execute(w, struct {
+		AppURL        template.URL
+		WebURL        string
+		ReportURL     string
+		Token         string
+		Rule          string
+		Variant       string
+		Source        string
+		TimeoutMillis int64
+	}{
+		AppURL:        template.URL(page.AppURL),
+		WebURL:        page.WebURL,
+		ReportURL:     page.ReportURL,
+		Token:         page.Token,
+		Rule:          page.Rule,
+		Variant:       page.Variant,
+		Source:        page.Source,
+		TimeoutMillis: handoffTimeout.Milliseconds(),
+	})
+	if err != nil {
+		logger.Error("failed to write handoff.html", "err", err)
+	}
+}
+
+// PreviewPage describes where a link leads; ContinueURL follows the link.
+type PreviewPage struct {
+	TargetURL   string
+	TargetHost  string
+	CreatedAt   time.Time
+	TotalClicks int64
+	ContinueURL string
+
+	PageTitle       string
+	PageDescription string
+	ImageURL        string
+	FaviconURL      string
+}
+
+// RenderPreviewPage renders the page that shows a link's destination instead of redirecting to it.
+func RenderPreviewPage(w http.ResponseWriter, page PreviewPage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+
+	if err := previewTemplate.Execute(w, page); err != nil {
+		logger.Error("failed to write preview.html", "err", err)
+	}
+}
@@ -0,0 +1,26 @@
+package validation
+
+import (
+	"fmt"
+	"shortener/src/internal/application/config"
+	shortlink "shortener/src/internal/domain/short_link"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// AliasTag validates a custom short code against the configured length and the short code character set.
+const AliasTag = "alias"
+
+func New(cfg config.ShortCodeConfig) (*validator.Validate, error) {
+	validate := validator.New()
+
+	err := validate.RegisterValidation(AliasTag, func(fl validator.FieldLevel) bool {
+		alias := fl.Field().String()
+		return len(alias) >= cfg.AliasMinLength && len(alias) <= cfg.AliasMaxLength && shortlink.ValidAlias(alias)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("register %s validation: %w", AliasTag, err)
+	}
+
+	return validate, nil
+}